@@ -0,0 +1,20 @@
+package configassets
+
+import _ "embed"
+
+// Schema is the embedded copy of `schemas/groningen/v1.0.0/config.schema.json`
+// and Defaults of `config/groningen/v1.0.0/groningen-defaults.yaml`, mirrored
+// into a Go-embeddable location so the `config` commands work from a
+// standalone binary.
+//
+// They are kept in sync via `make sync-embedded-config`.
+var (
+	//go:embed config.schema.json
+	Schema []byte
+
+	//go:embed groningen-defaults.yaml
+	Defaults []byte
+)
+
+// SchemaID identifies the embedded schema
+const SchemaID = "groningen/v1.0.0/config"
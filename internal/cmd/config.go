@@ -0,0 +1,320 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fulmenhq/gofulmen/schema"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	configassets "github.com/fulmenhq/forge-workhorse-groningen/internal/assets/config"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+	errwrap "github.com/fulmenhq/forge-workhorse-groningen/internal/errors"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+// Sources a config value can come from, highest precedence first
+const (
+	configSourceFlag    = "flag"
+	configSourceEnv     = "env"
+	configSourceFile    = "file"
+	configSourceDefault = "default"
+)
+
+var (
+	configShowFormat string
+	configInitForce  bool
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect, validate and create configuration",
+	Long: `Inspect, validate and create configuration.
+
+Values are resolved in this order: command-line flags, environment
+variables, the config file, then built-in defaults. 'config show' prints
+every key with the layer its value came from.`,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration and where each value came from",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		settings, err := effectiveConfig()
+		if err != nil {
+			return errwrap.WrapConfigInvalid(cmd.Context(), err, "failed to resolve configuration")
+		}
+
+		switch configShowFormat {
+		case "table":
+			return writeConfigTable(cmd.OutOrStdout(), viper.ConfigFileUsed(), settings)
+		case "json":
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(map[string]any{
+				"config_file": viper.ConfigFileUsed(),
+				"settings":    settings,
+			})
+		default:
+			return errwrap.NewInvalidInputError(fmt.Sprintf("unknown format %q (want table or json)", configShowFormat))
+		}
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Validate a config file against the config schema",
+	Long: `Validate a config file against the ` + configassets.SchemaID + ` schema.
+
+Without an argument the config file in use (--config or the discovered
+user config) is validated.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := viper.ConfigFileUsed()
+		if len(args) == 1 {
+			path = args[0]
+		}
+		if path == "" {
+			return errwrap.NewInvalidInputError("no config file found; pass a path or use --config")
+		}
+		if _, err := os.Stat(path); err != nil {
+			return errwrap.WrapInvalidInput(cmd.Context(), err, "cannot read "+path)
+		}
+
+		problems, err := validateConfigFile(path)
+		if err != nil {
+			return errwrap.WrapConfigInvalid(cmd.Context(), err, "failed to validate "+path)
+		}
+		out := cmd.OutOrStdout()
+		if len(problems) > 0 {
+			for _, problem := range problems {
+				_, _ = fmt.Fprintln(out, problem)
+			}
+			return errwrap.NewConfigInvalidError(fmt.Sprintf("%s: %d schema violation(s)", path, len(problems)))
+		}
+		_, err = fmt.Fprintf(out, "%s: valid (%s)\n", path, configassets.SchemaID)
+		return err
+	},
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write a commented starter config file to the user config path",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		identity := GetAppIdentity()
+		if identity == nil {
+			return errwrap.NewInternalError("app identity is not loaded")
+		}
+		// The file initConfig reads when --config is not given, not the
+		// loader's user paths, whose first entry the CLI never reads
+		path, err := userConfigFile(identity.ConfigName)
+		if err != nil {
+			return errwrap.WrapInternal(cmd.Context(), err, "no user config directory")
+		}
+
+		if _, err := os.Stat(path); err == nil && !configInitForce {
+			return errwrap.NewConflictError(path + " already exists (use --force to overwrite)")
+		} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return errwrap.WrapInternal(cmd.Context(), err, "cannot check "+path)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return errwrap.WrapInternal(cmd.Context(), err, "failed to create "+filepath.Dir(path))
+		}
+		if err := os.WriteFile(path, starterConfig(identity.BinaryName), 0o644); err != nil {
+			return errwrap.WrapInternal(cmd.Context(), err, "failed to write "+path)
+		}
+
+		_, err = fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s\n", path)
+		return err
+	},
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the config JSON schema",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, err := cmd.OutOrStdout().Write(configassets.Schema)
+		return err
+	},
+}
+
+// configSetting is one resolved config key
+type configSetting struct {
+	Key    string `json:"key"`
+	Value  any    `json:"value"`
+	Source string `json:"source"`
+}
+
+// effectiveConfig returns every known key, sorted, with its redacted value
+// and the layer it came from
+func effectiveConfig() ([]configSetting, error) {
+	redaction := config.LogRedactionConfig{
+		Enabled:  true,
+		Mode:     "mask",
+		Fields:   viper.GetStringSlice("logging.redaction.fields"),
+		Patterns: viper.GetStringSlice("logging.redaction.patterns"),
+	}
+	redactor, err := observability.NewRedactor(redaction)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := viper.AllKeys()
+	slices.Sort(keys)
+	settings := make([]configSetting, 0, len(keys))
+	for _, key := range keys {
+		settings = append(settings, configSetting{
+			Key:    key,
+			Value:  redactor.Value(key, viper.Get(key)),
+			Source: configSource(key),
+		})
+	}
+	return settings, nil
+}
+
+// configSource reports which layer the value of key came from
+func configSource(key string) string {
+	if flag, ok := flagBindings[key]; ok && flag.Changed {
+		return configSourceFlag
+	}
+	if _, set := os.LookupEnv(configEnvName(key)); set {
+		return configSourceEnv
+	}
+	if viper.InConfig(key) {
+		return configSourceFile
+	}
+	return configSourceDefault
+}
+
+// configEnvName is the environment variable viper reads for key: the
+// upper-cased key behind the app's env prefix
+func configEnvName(key string) string {
+	prefix := ""
+	if identity := GetAppIdentity(); identity != nil {
+		prefix = identity.EnvPrefix
+	}
+	if prefix == "" {
+		return strings.ToUpper(key)
+	}
+	return strings.ToUpper(prefix + "_" + key)
+}
+
+func writeConfigTable(w io.Writer, configFile string, settings []configSetting) error {
+	if configFile == "" {
+		configFile = "(none)"
+	}
+	if _, err := fmt.Fprintf(w, "Config file: %s\n\n", configFile); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+	for _, setting := range settings {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", setting.Key, formatConfigValue(setting.Value), setting.Source)
+	}
+	return tw.Flush()
+}
+
+// formatConfigValue prints scalars as is and lists and maps as JSON
+func formatConfigValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []any, []string, map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// validateConfigFile validates path against the embedded schema and returns
+// one "pointer: message" line per violation
+func validateConfigFile(path string) ([]string, error) {
+	validator, err := schema.NewValidator(configassets.Schema)
+	if err != nil {
+		return nil, err
+	}
+	diagnostics, err := validator.ValidateFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	for _, diag := range diagnostics {
+		if diag.Severity != schema.SeverityError || !leafDiagnostic(diag, diagnostics) {
+			continue
+		}
+		pointer := diag.Pointer
+		if pointer == "" {
+			pointer = "/"
+		}
+		problems = append(problems, pointer+": "+diag.Message)
+	}
+	// The validator reports in no fixed order
+	slices.Sort(problems)
+	return problems, nil
+}
+
+// leafDiagnostic reports whether diag is the root cause rather than one of
+// the "allOf failed" style wrappers the validator reports around it
+func leafDiagnostic(diag schema.Diagnostic, all []schema.Diagnostic) bool {
+	if diag.Message == "" {
+		return false
+	}
+	for _, other := range all {
+		if other.Keyword != diag.Keyword && strings.HasPrefix(other.Keyword, diag.Keyword) {
+			return false
+		}
+	}
+	return true
+}
+
+// starterConfig is the defaults file with a header for a user config
+func starterConfig(binaryName string) []byte {
+	body := configassets.Defaults
+	// Drop the defaults file's own header
+	for bytes.HasPrefix(body, []byte("#")) {
+		end := bytes.IndexByte(body, '\n')
+		if end < 0 {
+			break
+		}
+		body = body[end+1:]
+	}
+
+	header := fmt.Sprintf(`# %[1]s configuration
+#
+# Generated by '%[1]s config init' with the built-in defaults. Every key is
+# optional: delete what you don't need to change. Values are resolved in this
+# order: command-line flags, environment variables, this file, then built-in
+# defaults.
+#
+# Check the effective values with '%[1]s config show' and validate edits with
+# '%[1]s config validate'.
+
+`, binaryName)
+	return append([]byte(header), body...)
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configShowCmd, configValidateCmd, configInitCmd, configSchemaCmd)
+
+	configShowCmd.Flags().StringVarP(&configShowFormat, "format", "f", "table", "output format: table or json")
+	configInitCmd.Flags().BoolVar(&configInitForce, "force", false, "overwrite an existing config file")
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fulmenhq/gofulmen/appidentity"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	configassets "github.com/fulmenhq/forge-workhorse-groningen/internal/assets/config"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestConfigSourceReportsLayers(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	t.Cleanup(func() { delete(flagBindings, "server.host") })
+
+	viper.SetConfigFile(writeConfigFile(t, "server:\n  port: 9000\nlogging:\n  level: warn\n"))
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatalf("ReadInConfig: %v", err)
+	}
+	setDefaults()
+	original := appIdentity
+	appIdentity = &appidentity.Identity{EnvPrefix: "GRONINGEN_"}
+	t.Cleanup(func() { appIdentity = original })
+	viper.SetEnvPrefix(appIdentity.EnvPrefix)
+	viper.AutomaticEnv()
+	t.Setenv("GRONINGEN__SERVER.PORT", "7000")
+	flags := pflag.NewFlagSet("serve", pflag.ContinueOnError)
+	flags.String("host", "localhost", "")
+	bindFlag("server.host", flags.Lookup("host"))
+	if err := flags.Parse([]string{"--host", "0.0.0.0"}); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	settings, err := effectiveConfig()
+	if err != nil {
+		t.Fatalf("effectiveConfig: %v", err)
+	}
+	got := map[string]configSetting{}
+	for _, setting := range settings {
+		got[setting.Key] = setting
+	}
+
+	want := map[string]string{
+		"server.host":   configSourceFlag,
+		"server.port":   configSourceEnv,
+		"logging.level": configSourceFile,
+		"metrics.port":  configSourceDefault,
+	}
+	for key, source := range want {
+		if got[key].Source != source {
+			t.Errorf("%s source = %q, want %q", key, got[key].Source, source)
+		}
+	}
+	if viper.GetInt("server.port") != 7000 || viper.GetString("server.host") != "0.0.0.0" {
+		t.Errorf("server = %s:%d, want 0.0.0.0:7000", viper.GetString("server.host"), viper.GetInt("server.port"))
+	}
+}
+
+func TestEffectiveConfigRedactsSecrets(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	viper.SetConfigFile(writeConfigFile(t, "admin:\n  token: hunter2\nmetrics:\n  push:\n    otlp:\n      headers:\n        Authorization: Bearer abc\n"))
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatalf("ReadInConfig: %v", err)
+	}
+
+	var out bytes.Buffer
+	settings, err := effectiveConfig()
+	if err != nil {
+		t.Fatalf("effectiveConfig: %v", err)
+	}
+	if err := writeConfigTable(&out, viper.ConfigFileUsed(), settings); err != nil {
+		t.Fatalf("writeConfigTable: %v", err)
+	}
+	if strings.Contains(out.String(), "hunter2") || strings.Contains(out.String(), "Bearer abc") {
+		t.Errorf("secrets leaked:\n%s", out.String())
+	}
+}
+
+func TestValidateConfigFile(t *testing.T) {
+	problems, err := validateConfigFile(writeConfigFile(t, string(starterConfig("groningen"))))
+	if err != nil {
+		t.Fatalf("validateConfigFile: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("the starter config should be valid, got %v", problems)
+	}
+
+	problems, err = validateConfigFile(writeConfigFile(t, "server:\n  port: eighty\nlogging:\n  level: loud\n"))
+	if err != nil {
+		t.Fatalf("validateConfigFile: %v", err)
+	}
+	if len(problems) != 2 || !strings.HasPrefix(problems[0], "/logging/level: ") || !strings.HasPrefix(problems[1], "/server/port: ") {
+		t.Errorf("problems = %q, want one each for /server/port and /logging/level", problems)
+	}
+}
+
+func TestConfigInitWritesStarterFile(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	t.Cleanup(func() { configInitForce = false })
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	run := func(args ...string) error {
+		rootCmd.SetArgs(append([]string{"config", "init"}, args...))
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetErr(&bytes.Buffer{})
+		return rootCmd.Execute()
+	}
+	if err := run(); err != nil {
+		t.Fatalf("config init: %v", err)
+	}
+
+	path := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "groningen", "config.yaml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected %s: %v", path, err)
+	}
+	if !strings.HasPrefix(string(data), "# groningen configuration") {
+		t.Errorf("starter file header = %q", strings.SplitN(string(data), "\n", 2)[0])
+	}
+
+	if err := run(); err == nil {
+		t.Error("config init should refuse to overwrite an existing file")
+	}
+	if err := run("--force"); err != nil {
+		t.Errorf("config init --force: %v", err)
+	}
+}
+
+func TestEmbeddedSchemaID(t *testing.T) {
+	if !bytes.Contains(configassets.Schema, []byte(`"$id": "`+configassets.SchemaID+`"`)) {
+		t.Errorf("embedded schema does not declare $id %q", configassets.SchemaID)
+	}
+}
+
+func TestConfigInitWritesTheFileInitConfigReads(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	rootCmd.SetArgs([]string{"config", "init"})
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("config init: %v", err)
+	}
+
+	// Without --config the CLI must pick up the file config init wrote
+	viper.Reset()
+	original := cfgFile
+	cfgFile = ""
+	t.Cleanup(func() { cfgFile = original })
+	initConfig()
+
+	want := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "groningen", "config.yaml")
+	if got := viper.ConfigFileUsed(); got != want {
+		t.Errorf("initConfig read %q, config init wrote %q", got, want)
+	}
+}
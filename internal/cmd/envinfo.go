@@ -15,7 +15,10 @@ import (
 var envInfoCmd = &cobra.Command{
 	Use:   "envinfo",
 	Short: "Display environment information",
-	Long:  "Display comprehensive environment, configuration, and version information.",
+	Long: `Display comprehensive environment, configuration, and version information.
+
+Only the main config keys are shown; 'config show' lists every key and
+where its value came from.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		version := crucible.GetVersion()
 
@@ -1,6 +1,10 @@
 package cmd
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/fulmenhq/gofulmen/foundry"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -9,11 +13,34 @@ import (
 	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
 )
 
+var (
+	healthRemote  bool
+	healthAddress string
+	healthProbes  []string
+	healthTimeout time.Duration
+)
+
 var healthCmd = &cobra.Command{
 	Use:   "health",
 	Short: "Run self-health check",
-	Long:  "Run a self-health check to verify the application can start successfully.",
+	Long: `Run a self-health check to verify the application can start successfully.
+
+With --remote, query a running instance's health probes instead and exit
+non-zero if any probe fails. This makes the binary usable as a container
+HEALTHCHECK without curl:
+
+  HEALTHCHECK CMD ["/groningen", "health", "--remote"]
+
+Address forms (--address, defaults to server.host/server.port from config):
+  localhost:8080        plain TCP
+  http://host:8080      explicit URL
+  unix:///run/app.sock  unix domain socket`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if healthRemote {
+			runRemoteHealthCommand(cmd)
+			return
+		}
+
 		observability.CLILogger.Info("Running health check...")
 
 		// Check 1: Version info available
@@ -42,6 +69,59 @@ var healthCmd = &cobra.Command{
 	},
 }
 
+// runRemoteHealthCommand probes a running instance and exits with a foundry code
+func runRemoteHealthCommand(cmd *cobra.Command) {
+	address := healthAddress
+	if address == "" {
+		address = defaultRemoteAddress()
+	}
+
+	target, err := newRemoteTarget(address, healthTimeout)
+	if err != nil {
+		ExitWithCode(observability.CLILogger, foundry.ExitInvalidArgument, "Invalid --address", errwrap.WrapInvalidInput(cmd.Context(), err, "invalid remote address"))
+		return
+	}
+
+	results, err := runRemoteHealth(cmd.Context(), target, healthProbes)
+	if err != nil {
+		ExitWithCode(observability.CLILogger, foundry.ExitInvalidArgument, "Invalid --probe value", errwrap.WrapInvalidInput(cmd.Context(), err, "invalid probe"))
+		return
+	}
+
+	observability.CLILogger.Info("Checking remote health at " + target.baseURL)
+	for _, r := range results {
+		fields := []zap.Field{
+			zap.String("probe", r.Probe),
+			zap.String("url", r.URL),
+			zap.Duration("duration", r.Duration),
+		}
+		switch {
+		case r.Err != nil:
+			observability.CLILogger.Warn(fmt.Sprintf("❌ %s: %v", r.Probe, r.Err), append(fields, zap.Error(r.Err))...)
+		case !r.Healthy():
+			msg := fmt.Sprintf("❌ %s: HTTP %d", r.Probe, r.StatusCode)
+			if len(r.Failing) > 0 {
+				msg += " (" + strings.Join(r.Failing, ", ") + ")"
+			}
+			observability.CLILogger.Warn(msg, append(fields, zap.Int("status_code", r.StatusCode))...)
+		default:
+			observability.CLILogger.Info(fmt.Sprintf("✅ %s: %s", r.Probe, r.Status), append(fields, zap.Int("status_code", r.StatusCode))...)
+		}
+	}
+
+	observability.CLILogger.Info("")
+	if code := remoteExitCode(results); code != foundry.ExitSuccess {
+		ExitWithCode(observability.CLILogger, code, "Remote health check failed", errwrap.NewExternalServiceError("one or more health probes failed"))
+		return
+	}
+	observability.CLILogger.Info("✅ All remote health probes passed")
+}
+
 func init() {
 	rootCmd.AddCommand(healthCmd)
+
+	healthCmd.Flags().BoolVar(&healthRemote, "remote", false, "check the health probes of a running instance")
+	healthCmd.Flags().StringVar(&healthAddress, "address", "", "instance address for --remote (host:port, http(s)://host:port, or unix:///path.sock)")
+	healthCmd.Flags().StringSliceVar(&healthProbes, "probe", []string{"live", "ready", "startup"}, "probes to check in --remote mode (live, ready, startup, aggregate)")
+	healthCmd.Flags().DurationVar(&healthTimeout, "timeout", 3*time.Second, "per-probe request timeout in --remote mode")
 }
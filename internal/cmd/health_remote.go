@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fulmenhq/gofulmen/foundry"
+	"github.com/spf13/viper"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/server/handlers"
+)
+
+// defaultProbePaths maps probe names to their standard routes
+var defaultProbePaths = map[string]string{
+	handlers.ProbeAggregate: "/health",
+	handlers.ProbeLiveness:  "/health/live",
+	handlers.ProbeReadiness: "/health/ready",
+	handlers.ProbeStartup:   "/health/startup",
+}
+
+// remoteTarget describes how to reach a running instance
+type remoteTarget struct {
+	baseURL string
+	client  *http.Client
+}
+
+// remoteProbeResult captures the outcome of a single remote probe call
+type remoteProbeResult struct {
+	Probe      string
+	URL        string
+	StatusCode int
+	Status     string
+	Failing    []string
+	Duration   time.Duration
+	Err        error
+}
+
+// Healthy reports whether the probe returned a 2xx response
+func (r remoteProbeResult) Healthy() bool {
+	return r.Err == nil && r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// newRemoteTarget parses a --address value. Supported forms:
+//
+//	unix:///run/app.sock   unix domain socket
+//	http(s)://host:port    explicit URL
+//	host:port or :port     plain TCP (http)
+func newRemoteTarget(address string, timeout time.Duration) (*remoteTarget, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	target := &remoteTarget{
+		client: &http.Client{Timeout: timeout, Transport: transport},
+	}
+
+	switch {
+	case address == "":
+		return nil, fmt.Errorf("remote address is empty")
+	case strings.HasPrefix(address, "unix:"):
+		socketPath := strings.TrimPrefix(strings.TrimPrefix(address, "unix:"), "//")
+		if socketPath == "" {
+			return nil, fmt.Errorf("unix socket path is empty in %q", address)
+		}
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socketPath)
+		}
+		target.baseURL = "http://unix"
+	case strings.HasPrefix(address, "http://"), strings.HasPrefix(address, "https://"):
+		target.baseURL = strings.TrimSuffix(address, "/")
+	default:
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid remote address %q: %w", address, err)
+		}
+		if host == "" {
+			host = "localhost"
+		}
+		target.baseURL = "http://" + net.JoinHostPort(host, port)
+	}
+
+	return target, nil
+}
+
+// defaultRemoteAddress derives a local address from the server configuration
+func defaultRemoteAddress() string {
+	host := viper.GetString("server.host")
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	port := viper.GetInt("server.port")
+	if port == 0 {
+		port = 8080
+	}
+	return net.JoinHostPort(host, fmt.Sprint(port))
+}
+
+// probePath resolves the configured route for a probe
+func probePath(probe string) (string, error) {
+	defaultPath, ok := defaultProbePaths[probe]
+	if !ok {
+		return "", fmt.Errorf("unknown probe %q (valid: live, ready, startup, aggregate)", probe)
+	}
+	if path := viper.GetString("health.probes." + probe + ".path"); path != "" {
+		return path, nil
+	}
+	return defaultPath, nil
+}
+
+// check calls a single probe and summarizes the response
+func (t *remoteTarget) check(ctx context.Context, probe, path string) remoteProbeResult {
+	result := remoteProbeResult{Probe: probe, URL: t.baseURL + path}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, result.URL, nil)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	start := time.Now()
+	resp, err := t.client.Do(req)
+	if err != nil {
+		result.Duration = time.Since(start)
+		result.Err = err
+		return result
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	result.Duration = time.Since(start)
+	result.StatusCode = resp.StatusCode
+
+	// Successful probes return {"status": ...}; failures return an error envelope
+	var payload struct {
+		Status string `json:"status"`
+		Error  struct {
+			Details struct {
+				Status string            `json:"status"`
+				Checks map[string]string `json:"checks"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &payload) == nil {
+		result.Status = payload.Status
+		if result.Status == "" {
+			result.Status = payload.Error.Details.Status
+		}
+		for name, status := range payload.Error.Details.Checks {
+			if status != "healthy" && status != "disabled" {
+				result.Failing = append(result.Failing, name+"="+status)
+			}
+		}
+		sort.Strings(result.Failing)
+	}
+
+	return result
+}
+
+// runRemoteHealth checks each probe against the remote target in order
+func runRemoteHealth(ctx context.Context, target *remoteTarget, probes []string) ([]remoteProbeResult, error) {
+	results := make([]remoteProbeResult, 0, len(probes))
+	for _, probe := range probes {
+		path, err := probePath(probe)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, target.check(ctx, probe, path))
+	}
+	return results, nil
+}
+
+// remoteExitCode maps probe results to a foundry exit code
+func remoteExitCode(results []remoteProbeResult) foundry.ExitCode {
+	for _, r := range results {
+		if r.Err == nil {
+			continue
+		}
+		var netErr net.Error
+		switch {
+		case errors.Is(r.Err, context.DeadlineExceeded), errors.As(r.Err, &netErr) && netErr.Timeout():
+			return foundry.ExitConnectionTimeout
+		case errors.Is(r.Err, syscall.ECONNREFUSED), errors.Is(r.Err, os.ErrNotExist):
+			return foundry.ExitConnectionRefused
+		default:
+			return foundry.ExitNetworkUnreachable
+		}
+	}
+	for _, r := range results {
+		if !r.Healthy() {
+			return foundry.ExitHealthCheckFailed
+		}
+	}
+	return foundry.ExitSuccess
+}
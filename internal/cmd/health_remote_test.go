@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fulmenhq/gofulmen/foundry"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/server/handlers"
+)
+
+func TestNewRemoteTargetParsesAddresses(t *testing.T) {
+	cases := map[string]string{
+		"localhost:8080":          "http://localhost:8080",
+		":9000":                   "http://localhost:9000",
+		"http://10.0.0.1:8080/":   "http://10.0.0.1:8080",
+		"https://svc.example:443": "https://svc.example:443",
+		"unix:///run/app.sock":    "http://unix",
+	}
+	for address, want := range cases {
+		target, err := newRemoteTarget(address, time.Second)
+		if err != nil {
+			t.Fatalf("newRemoteTarget(%q) returned error: %v", address, err)
+		}
+		if target.baseURL != want {
+			t.Fatalf("newRemoteTarget(%q) baseURL = %q, want %q", address, target.baseURL, want)
+		}
+	}
+
+	for _, address := range []string{"", "no-port", "unix:"} {
+		if _, err := newRemoteTarget(address, time.Second); err == nil {
+			t.Fatalf("expected error for address %q", address)
+		}
+	}
+}
+
+func TestRunRemoteHealth(t *testing.T) {
+	manager := handlers.NewHealthManager("test")
+	manager.RegisterChecker("db", &remoteStubChecker{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health/live", manager.LivenessHandler)
+	mux.HandleFunc("/health/ready", manager.ReadinessHandler)
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	target, err := newRemoteTarget(srv.URL, time.Second)
+	if err != nil {
+		t.Fatalf("failed to create target: %v", err)
+	}
+
+	results, err := runRemoteHealth(context.Background(), target, []string{"live", "ready"})
+	if err != nil {
+		t.Fatalf("runRemoteHealth returned error: %v", err)
+	}
+	if code := remoteExitCode(results); code != foundry.ExitSuccess {
+		t.Fatalf("expected success exit code, got %d (%+v)", code, results)
+	}
+
+	// Unknown probe names are rejected before any request is made
+	if _, err := runRemoteHealth(context.Background(), target, []string{"bogus"}); err == nil {
+		t.Fatal("expected error for unknown probe")
+	}
+}
+
+func TestRunRemoteHealthReportsFailures(t *testing.T) {
+	manager := handlers.NewHealthManager("test")
+	manager.RegisterChecker("db", &remoteStubChecker{err: context.Canceled})
+
+	srv := httptest.NewServer(http.HandlerFunc(manager.ReadinessHandler))
+	defer srv.Close()
+
+	target, _ := newRemoteTarget(srv.URL, time.Second)
+	results, err := runRemoteHealth(context.Background(), target, []string{"ready"})
+	if err != nil {
+		t.Fatalf("runRemoteHealth returned error: %v", err)
+	}
+
+	if results[0].StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", results[0].StatusCode)
+	}
+	if len(results[0].Failing) != 1 || results[0].Failing[0] != "db=unhealthy" {
+		t.Fatalf("expected failing db check, got %v", results[0].Failing)
+	}
+	if code := remoteExitCode(results); code != foundry.ExitHealthCheckFailed {
+		t.Fatalf("expected health check failed exit code, got %d", code)
+	}
+}
+
+func TestRunRemoteHealthOverUnixSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "health.sock")
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+
+	manager := handlers.NewHealthManager("test")
+	srv := &http.Server{Handler: http.HandlerFunc(manager.LivenessHandler)}
+	go func() { _ = srv.Serve(ln) }()
+	defer func() { _ = srv.Close() }()
+
+	target, err := newRemoteTarget("unix://"+socketPath, time.Second)
+	if err != nil {
+		t.Fatalf("failed to create target: %v", err)
+	}
+
+	results, err := runRemoteHealth(context.Background(), target, []string{"live"})
+	if err != nil {
+		t.Fatalf("runRemoteHealth returned error: %v", err)
+	}
+	if !results[0].Healthy() {
+		t.Fatalf("expected healthy result over unix socket, got %+v", results[0])
+	}
+}
+
+func TestRemoteExitCodeConnectionRefused(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("loopback listen not permitted: %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	target, _ := newRemoteTarget(addr, time.Second)
+	results, _ := runRemoteHealth(context.Background(), target, []string{"live"})
+	if code := remoteExitCode(results); code != foundry.ExitConnectionRefused {
+		t.Fatalf("expected connection refused exit code, got %d (%v)", code, results[0].Err)
+	}
+}
+
+type remoteStubChecker struct {
+	err error
+}
+
+func (s *remoteStubChecker) CheckHealth(ctx context.Context) error {
+	return s.err
+}
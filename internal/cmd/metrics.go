@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	errwrap "github.com/fulmenhq/forge-workhorse-groningen/internal/errors"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/metrics"
+)
+
+var (
+	metricsCatalogFormat string
+	metricsNamespace     string
+	metricsOutput        string
+)
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Inspect the metrics this service emits",
+	Long: `Inspect the metrics catalog and generate monitoring assets from it.
+
+The catalog lists every metric name, type, unit, label set and description.
+Dashboards and alert rules are generated from the same catalog, so they
+follow metric renames instead of drifting from the code.`,
+}
+
+var metricsCatalogCmd = &cobra.Command{
+	Use:   "catalog",
+	Short: "List every metric with its type, labels and description",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var buf bytes.Buffer
+		if err := metrics.WriteCatalog(&buf, metricsCatalogFormat, resolveMetricsNamespace()); err != nil {
+			return errwrap.NewInvalidInputError(err.Error())
+		}
+		return writeMetricsOutput(cmd, buf.Bytes())
+	},
+}
+
+var metricsDashboardCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Generate a starter Grafana dashboard (JSON)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dashboard, err := metrics.GrafanaDashboard(resolveMetricsNamespace())
+		if err != nil {
+			return errwrap.WrapInternal(cmd.Context(), err, "failed to generate dashboard")
+		}
+		return writeMetricsOutput(cmd, dashboard)
+	},
+}
+
+var metricsAlertsCmd = &cobra.Command{
+	Use:   "alerts",
+	Short: "Generate a Prometheus alerting rules file (YAML)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return writeMetricsOutput(cmd, metrics.AlertRules(resolveMetricsNamespace()))
+	},
+}
+
+// resolveMetricsNamespace defaults to the prefix `serve` applies to metric names
+func resolveMetricsNamespace() string {
+	if metricsNamespace != "" {
+		return metricsNamespace
+	}
+	if identity := GetAppIdentity(); identity != nil {
+		return identity.TelemetryNamespace()
+	}
+	return "groningen"
+}
+
+// writeMetricsOutput writes to --output, or stdout when it is empty or "-"
+func writeMetricsOutput(cmd *cobra.Command, data []byte) error {
+	if metricsOutput == "" || metricsOutput == "-" {
+		_, err := io.Copy(cmd.OutOrStdout(), bytes.NewReader(data))
+		return err
+	}
+	if err := os.WriteFile(metricsOutput, data, 0o644); err != nil {
+		return errwrap.WrapInternal(cmd.Context(), err, "failed to write "+metricsOutput)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(metricsCmd)
+	metricsCmd.AddCommand(metricsCatalogCmd, metricsDashboardCmd, metricsAlertsCmd)
+
+	metricsCmd.PersistentFlags().StringVar(&metricsNamespace, "namespace", "", "metric name prefix (default: the app's telemetry namespace)")
+	metricsCmd.PersistentFlags().StringVarP(&metricsOutput, "output", "o", "", "write to a file instead of stdout")
+	metricsCatalogCmd.Flags().StringVarP(&metricsCatalogFormat, "format", "f", metrics.CatalogFormatTable, "output format: table, json or markdown")
+}
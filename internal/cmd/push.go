@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+	errwrap "github.com/fulmenhq/forge-workhorse-groningen/internal/errors"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/metrics"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+// pushFlushTimeout bounds the final push when a command other than serve exits
+const pushFlushTimeout = 10 * time.Second
+
+// startMetricsPush runs before every command. Commands other than serve are
+// never scraped, so when a push exporter is enabled it initializes metrics
+// and starts the exporters; serve sets up metrics itself.
+func startMetricsPush(cmd *cobra.Command, _ []string) error {
+	if cmd == serveCmd || !viper.GetBool("metrics.enabled") {
+		return nil
+	}
+
+	var metricsCfg config.MetricsConfig
+	if err := unmarshalSection("metrics", &metricsCfg); err != nil {
+		return errwrap.WrapConfigInvalid(cmd.Context(), err, "invalid metrics configuration")
+	}
+	push := metricsCfg.Push
+	if !push.OTLP.Enabled && !push.StatsD.Enabled && !push.RemoteWrite.Enabled {
+		return nil
+	}
+
+	identity := GetAppIdentity()
+	if err := observability.InitMetrics(identity.BinaryName, 0, identity.TelemetryNamespace()); err != nil {
+		return errwrap.WrapInternal(cmd.Context(), err, "metrics initialization failed")
+	}
+	return applyMetricsConfig(cmd, metricsCfg)
+}
+
+// applyMetricsConfig applies bucket and cardinality settings, publishes
+// app_build_info and starts the push exporters. Metrics must be initialized.
+func applyMetricsConfig(cmd *cobra.Command, metricsCfg config.MetricsConfig) error {
+	if err := observability.SetHistogramBuckets(metricsCfg.Buckets); err != nil {
+		return errwrap.WrapConfigInvalid(cmd.Context(), err, "invalid metrics.buckets configuration")
+	}
+	observability.SetMaxLabelValues(metricsCfg.MaxLabelValues)
+	metrics.SetBuildInfo(versionInfo.Version, versionInfo.Commit)
+
+	if err := observability.StartPushExporters(metricsCfg.Push); err != nil {
+		return errwrap.WrapConfigInvalid(cmd.Context(), err, "invalid metrics.push configuration")
+	}
+	return nil
+}
+
+// flushMetricsPush runs after every command and sends the final values of
+// any running push exporters. It is a no-op once serve has flushed them on
+// shutdown, and an unreachable collector never fails the command.
+func flushMetricsPush(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), pushFlushTimeout)
+	defer cancel()
+
+	if err := observability.FlushPushExporters(ctx); err != nil && observability.CLILogger != nil {
+		observability.CLILogger.Warn("Failed to flush metrics push exporters", zap.Error(err))
+	}
+	return nil
+}
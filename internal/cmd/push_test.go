@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+func TestNonServeCommandsFlushPushExporters(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(func() {
+		viper.Reset()
+		cfgFile = ""
+		rootCmd.SetArgs(nil)
+		observability.MetricsRegistry = nil
+		observability.TelemetrySystem = nil
+	})
+
+	var (
+		mu       sync.Mutex
+		payloads []string
+	)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		payloads = append(payloads, string(body))
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(collector.Close)
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	content := "metrics:\n  push:\n    otlp:\n      enabled: true\n      endpoint: " + collector.URL + "/v1/metrics\n      interval: 1h\n"
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCmd.SetArgs([]string{"--config", file, "version"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("version failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(payloads) != 1 {
+		t.Fatalf("expected one push when the command exits, got %d", len(payloads))
+	}
+	if !strings.Contains(payloads[0], "app_build_info") {
+		t.Errorf("expected the push to carry app_build_info, got %s", payloads[0])
+	}
+}
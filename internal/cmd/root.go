@@ -11,6 +11,7 @@ import (
 
 	"github.com/fulmenhq/forge-workhorse-groningen/internal/appid"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 
@@ -24,6 +25,10 @@ var (
 	// App identity loaded from .fulmen/app.yaml
 	appIdentity *appidentity.Identity
 
+	// Flags bound to config keys, used by `config show` to report where a
+	// value came from
+	flagBindings = map[string]*pflag.Flag{}
+
 	// Version info set by main package
 	versionInfo struct {
 		Version   string
@@ -63,12 +68,32 @@ func Execute() error {
 func init() {
 	cobra.OnInitialize(initConfig)
 
+	// Push exporters run for every command, not only serve
+	rootCmd.PersistentPreRunE = startMetricsPush
+	rootCmd.PersistentPostRunE = flushMetricsPush
+
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (optional; defaults to app identity config path)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output (sets log level to debug)")
 
 	// Bind flags to viper
-	_ = viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
+	bindFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
+}
+
+// bindFlag binds a flag to a config key so it overrides env, file and defaults
+func bindFlag(key string, flag *pflag.Flag) {
+	_ = viper.BindPFlag(key, flag)
+	flagBindings[key] = flag
+}
+
+// userConfigFile is the user config file initConfig reads first and
+// `config init` writes: $XDG_CONFIG_HOME/<config-name>/config.yaml
+func userConfigFile(configName string) (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, configName, "config.yaml"), nil
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -103,7 +128,7 @@ func initConfig() {
 		viper.SetConfigFile(cfgFile)
 	} else {
 		// Find XDG config directory
-		configDir, err := os.UserConfigDir()
+		configFile, err := userConfigFile(appIdentity.ConfigName)
 		if err != nil {
 			if verbose {
 				observability.CLILogger.Warn("Could not find user config directory", zap.Error(err))
@@ -117,12 +142,11 @@ func initConfig() {
 			viper.SetConfigName("." + appIdentity.ConfigName)
 		} else {
 			// Use XDG config directory with app identity
-			appConfigDir := configDir + "/" + appIdentity.ConfigName
-			viper.AddConfigPath(appConfigDir)
+			viper.AddConfigPath(filepath.Dir(configFile))
 			viper.SetConfigName("config")
 
 			// Also check legacy location (older templates hardcoded a name)
-			oldConfigDir := configDir + "/" + appIdentity.BinaryName
+			oldConfigDir := filepath.Join(filepath.Dir(filepath.Dir(configFile)), appIdentity.BinaryName)
 			viper.AddConfigPath(oldConfigDir)
 		}
 
@@ -168,13 +192,44 @@ func setDefaults() {
 	// Logging defaults
 	viper.SetDefault("logging.level", "info")
 	viper.SetDefault("logging.profile", "structured")
+	viper.SetDefault("logging.environment", "production")
+	viper.SetDefault("logging.throttling.enabled", false)
+	viper.SetDefault("logging.throttling.max_rate", 1000)
+	viper.SetDefault("logging.throttling.burst_size", 100)
+	viper.SetDefault("logging.redaction.enabled", true)
+	viper.SetDefault("logging.redaction.mode", "mask")
+	viper.SetDefault("logging.access.enabled", true)
+	// logging.access.exclude_paths defaults to the probe routes (see accessExcludePaths)
+	viper.SetDefault("logging.access.sample_rate", 1.0)
+	viper.SetDefault("logging.access.slow_threshold", "0s")
+	viper.SetDefault("logging.access.format", "json")
 
 	// Metrics defaults
 	viper.SetDefault("metrics.enabled", true)
 	viper.SetDefault("metrics.port", 9090)
+	viper.SetDefault("metrics.max_label_values", 100)
+	viper.SetDefault("metrics.push.otlp.endpoint", "http://localhost:4318/v1/metrics")
+	viper.SetDefault("metrics.push.otlp.interval", "15s")
+	viper.SetDefault("metrics.push.otlp.timeout", "5s")
+	viper.SetDefault("metrics.push.statsd.endpoint", "localhost:8125")
+	viper.SetDefault("metrics.push.statsd.interval", "10s")
+	viper.SetDefault("metrics.push.statsd.timeout", "5s")
+	viper.SetDefault("metrics.push.statsd.dogstatsd", true)
+	viper.SetDefault("metrics.push.remote_write.endpoint", "http://localhost:9090/api/v1/write")
+	viper.SetDefault("metrics.push.remote_write.interval", "30s")
+	viper.SetDefault("metrics.push.remote_write.timeout", "5s")
 
 	// Health check defaults
 	viper.SetDefault("health.enabled", true)
+	viper.SetDefault("health.probes.aggregate.path", "/health")
+	viper.SetDefault("health.probes.aggregate.timeout", "5s")
+	viper.SetDefault("health.probes.live.path", "/health/live")
+	viper.SetDefault("health.probes.live.timeout", "2s")
+	viper.SetDefault("health.probes.ready.path", "/health/ready")
+	viper.SetDefault("health.probes.ready.timeout", "5s")
+	viper.SetDefault("health.probes.startup.path", "/health/startup")
+	viper.SetDefault("health.probes.startup.timeout", "3s")
+	viper.SetDefault("health.history_size", 100)
 
 	// Worker defaults
 	viper.SetDefault("workers", 4)
@@ -10,10 +10,15 @@ import (
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
 	errwrap "github.com/fulmenhq/forge-workhorse-groningen/internal/errors"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/health"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/metrics"
 	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
 	"github.com/fulmenhq/forge-workhorse-groningen/internal/server"
 	"github.com/fulmenhq/forge-workhorse-groningen/internal/server/handlers"
+	servermw "github.com/fulmenhq/forge-workhorse-groningen/internal/server/middleware"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/slo"
 )
 
 var (
@@ -34,7 +39,10 @@ func (s signalHealthChecker) CheckHealth(ctx context.Context) error {
 type telemetryHealthChecker struct{}
 
 func (telemetryHealthChecker) CheckHealth(ctx context.Context) error {
-	if observability.TelemetrySystem == nil || observability.PrometheusExporter == nil {
+	if !observability.MetricsEnabled() {
+		return handlers.ErrCheckDisabled
+	}
+	if observability.TelemetrySystem == nil || observability.MetricsRegistry == nil {
 		return errwrap.NewInternalError("telemetry system not initialized")
 	}
 	return nil
@@ -59,6 +67,30 @@ func (i identityHealthChecker) CheckHealth(ctx context.Context) error {
 	return nil
 }
 
+// unmarshalSection decodes one top-level config section with its defaults.
+// viper.UnmarshalKey returns the config file's section as is once the file
+// sets any key in it, dropping nested defaults such as
+// logging.redaction.enabled; AllSettings merges them key by key. Decode
+// every config section through it rather than viper.UnmarshalKey.
+func unmarshalSection(key string, out any) error {
+	section, _ := viper.AllSettings()[key].(map[string]any)
+	merged := viper.New()
+	if err := merged.MergeConfigMap(section); err != nil {
+		return err
+	}
+	return merged.Unmarshal(out)
+}
+
+// accessExcludePaths is the default logging.access.exclude_paths: the routes
+// probes and scrapers hit, wherever health.probes puts them
+func accessExcludePaths(healthCfg config.HealthConfig) []string {
+	paths := []string{"/metrics"}
+	if healthCfg.Enabled {
+		paths = append(healthCfg.Probes.Routes(), paths...)
+	}
+	return paths
+}
+
 var serveCmd = &cobra.Command{
 	Use:   "serve",
 	Short: "Start the HTTP server",
@@ -75,29 +107,68 @@ The server will cleanly shut down the HTTP server and flush logs on shutdown.`,
 		identity := GetAppIdentity()
 		namespace := identity.TelemetryNamespace()
 
-		// Initialize server logger with namespace
-		logLevel := viper.GetString("logging.level")
-		observability.InitServerLogger(identity.BinaryName, logLevel, namespace)
-
-		metricsPort := viper.GetInt("metrics.port")
-		if metricsPort == 0 {
-			metricsPort = 9090
+		// Initialize server logger with namespace; the profile comes from logging config
+		var loggingCfg config.LoggingConfig
+		if err := unmarshalSection("logging", &loggingCfg); err != nil {
+			return errwrap.WrapConfigInvalid(cmd.Context(), err, "invalid logging configuration")
+		}
+		if err := observability.InitServerLogger(identity.BinaryName, loggingCfg, namespace); err != nil {
+			return errwrap.WrapConfigInvalid(cmd.Context(), err, "invalid logging configuration")
 		}
 
-		// Initialize metrics with namespace
-		if err := observability.InitMetrics(identity.BinaryName, metricsPort, namespace); err != nil {
-			observability.ServerLogger.Error("Failed to initialize metrics",
-				zap.Error(err))
-			return errwrap.WrapInternal(cmd.Context(), err, "metrics initialization failed")
+		if viper.GetBool("metrics.enabled") {
+			// metrics.port 0 disables the dedicated listener; both it and /metrics
+			// on the main router render through observability.WriteMetrics
+			metricsPort := viper.GetInt("metrics.port")
+
+			// Initialize metrics with namespace
+			if err := observability.InitMetrics(identity.BinaryName, metricsPort, namespace); err != nil {
+				observability.ServerLogger.Error("Failed to initialize metrics",
+					zap.Error(err))
+				return errwrap.WrapInternal(cmd.Context(), err, "metrics initialization failed")
+			}
+
+			var metricsCfg config.MetricsConfig
+			if err := unmarshalSection("metrics", &metricsCfg); err != nil {
+				return errwrap.WrapConfigInvalid(cmd.Context(), err, "invalid metrics configuration")
+			}
+
+			// Push exporters flush once more on shutdown (registered below)
+			if err := applyMetricsConfig(cmd, metricsCfg); err != nil {
+				return err
+			}
+
+			// Go runtime and process collectors (refreshed per scrape)
+			metrics.RegisterRuntimeCollectors()
+		} else {
+			observability.DisableMetrics()
+			observability.ServerLogger.Info("Metrics disabled (metrics.enabled=false)")
 		}
 
 		observability.ServerLogger.Info("Initializing server",
-			zap.String("service", identity.BinaryName),
-			zap.String("namespace", namespace),
 			zap.String("version", versionInfo.Version),
 			zap.String("host", serverHost),
 			zap.Int("port", serverPort),
-			zap.Int("metrics_port", metricsPort))
+			zap.Int("metrics_port", observability.GetMetricsPort()))
+
+		// SLO objectives are tracked from request data; gauges need metrics initialized first
+		var sloCfg config.SLOConfig
+		if err := unmarshalSection("slo", &sloCfg); err != nil {
+			return errwrap.WrapConfigInvalid(cmd.Context(), err, "invalid slo configuration")
+		}
+		if _, err := slo.Init(sloCfg); err != nil {
+			return errwrap.WrapConfigInvalid(cmd.Context(), err, "invalid slo.objectives configuration")
+		}
+		if len(sloCfg.Objectives) > 0 {
+			observability.ServerLogger.Info("Tracking SLO objectives",
+				zap.Int("count", len(sloCfg.Objectives)))
+		}
+
+		// Load health configuration (probes, checks)
+		var healthCfg config.HealthConfig
+		if err := unmarshalSection("health", &healthCfg); err != nil {
+			return errwrap.WrapConfigInvalid(cmd.Context(), err, "invalid health configuration")
+		}
 
 		// Initialize health manager
 		handlers.InitHealthManager(versionInfo.Version)
@@ -110,8 +181,39 @@ The server will cleanly shut down the HTTP server and flush logs on shutdown.`,
 			configName: identity.ConfigName,
 		})
 
+		// Register built-in dependency checks from health.checks
+		configuredCheckers, err := health.FromConfig(healthCfg.Checks)
+		if err != nil {
+			return errwrap.WrapConfigInvalid(cmd.Context(), err, "invalid health.checks configuration")
+		}
+		for name, checker := range configuredCheckers {
+			hm.RegisterChecker(name, checker)
+		}
+		if len(configuredCheckers) > 0 {
+			observability.ServerLogger.Info("Registered configured health checks",
+				zap.Int("count", len(configuredCheckers)))
+		}
+
+		// Apply per-probe timeouts
+		hm.SetProbeTimeout(handlers.ProbeAggregate, healthCfg.Probes.Aggregate.Timeout)
+		hm.SetProbeTimeout(handlers.ProbeLiveness, healthCfg.Probes.Live.Timeout)
+		hm.SetProbeTimeout(handlers.ProbeReadiness, healthCfg.Probes.Ready.Timeout)
+		hm.SetProbeTimeout(handlers.ProbeStartup, healthCfg.Probes.Startup.Timeout)
+		hm.SetHistorySize(healthCfg.HistorySize)
+
+		// Access log: exclusions, sampling, slow-request promotion and format
+		if !viper.IsSet("logging.access.exclude_paths") {
+			loggingCfg.Access.ExcludePaths = accessExcludePaths(healthCfg)
+		}
+		accessLog, err := servermw.NewAccessLogger(loggingCfg.Access)
+		if err != nil {
+			return errwrap.WrapConfigInvalid(cmd.Context(), err, "invalid logging.access configuration")
+		}
+
 		// Create server
-		srv := server.New(serverHost, serverPort)
+		srv := server.New(serverHost, serverPort,
+			server.WithHealthConfig(healthCfg),
+			server.WithAccessLogger(accessLog))
 
 		// Set app identity for handlers
 		handlers.SetAppIdentity(identity)
@@ -126,6 +228,13 @@ The server will cleanly shut down the HTTP server and flush logs on shutdown.`,
 		// Handler 1: Flush logger (executed last)
 		signals.OnShutdown(func(ctx context.Context) error {
 			observability.ServerLogger.Info("Flushing logger...")
+			flushCtx, cancel := context.WithTimeout(ctx, shutdownTimeout)
+			defer cancel()
+
+			// Shipped logs are the ones that can be lost, so their errors are reported
+			if err := observability.ServerLogger.Flush(flushCtx); err != nil {
+				observability.ServerLogger.Error("Failed to deliver shipped logs", zap.Error(err))
+			}
 			if err := observability.ServerLogger.Sync(); err != nil {
 				// Sync errors are often benign (stdout/stderr already closed)
 				observability.ServerLogger.Warn("Logger sync returned error (may be benign)",
@@ -134,7 +243,20 @@ The server will cleanly shut down the HTTP server and flush logs on shutdown.`,
 			return nil
 		})
 
-		// Handler 2: Shutdown HTTP server (executed first)
+		// Handler 2: Flush push exporters so the final values reach collectors
+		signals.OnShutdown(func(ctx context.Context) error {
+			flushCtx, cancel := context.WithTimeout(ctx, shutdownTimeout)
+			defer cancel()
+
+			if err := observability.FlushPushExporters(flushCtx); err != nil {
+				// A collector being unreachable must not block shutdown
+				observability.ServerLogger.Warn("Failed to flush metrics push exporters",
+					zap.Error(err))
+			}
+			return nil
+		})
+
+		// Handler 3: Shutdown HTTP server (executed first)
 		signals.OnShutdown(func(ctx context.Context) error {
 			observability.ServerLogger.Info("Shutting down HTTP server...")
 			shutdownCtx, cancel := context.WithTimeout(ctx, shutdownTimeout)
@@ -148,6 +270,17 @@ The server will cleanly shut down the HTTP server and flush logs on shutdown.`,
 			return nil
 		})
 
+		// Reopen log files on SIGHUP first, so logrotate works even if the config reload fails
+		signals.OnReload(func(ctx context.Context) error {
+			if err := observability.ServerLogger.ReopenFiles(); err != nil {
+				observability.ServerLogger.Error("Failed to reopen log files", zap.Error(err))
+			}
+			if err := accessLog.ReopenFiles(); err != nil {
+				observability.ServerLogger.Error("Failed to reopen access log file", zap.Error(err))
+			}
+			return nil
+		})
+
 		// Register config reload handler (SIGHUP)
 		signals.OnReload(func(ctx context.Context) error {
 			observability.ServerLogger.Info("Received SIGHUP: attempting config reload")
@@ -167,8 +300,15 @@ The server will cleanly shut down the HTTP server and flush logs on shutdown.`,
 			observability.ServerLogger.Info("Configuration reloaded successfully",
 				zap.String("file", viper.ConfigFileUsed()))
 
+			// Reapply logging.level; a temporary override from /admin/loglevel
+			// stays active until its TTL and then reverts to the new level
+			if severity, err := observability.ParseLevel(viper.GetString("logging.level")); err != nil {
+				observability.ServerLogger.Warn("Ignoring invalid logging.level on reload", zap.Error(err))
+			} else {
+				observability.ServerLogger.SetConfiguredLevel(severity)
+			}
+
 			// TODO: Add hooks for components that need to react to config changes
-			// - Update log levels if changed
 			// - Update metrics configuration if changed
 			// - Notify other components of config changes
 
@@ -218,6 +358,6 @@ func init() {
 	serveCmd.Flags().StringVar(&serverHost, "host", "localhost", "server host")
 	serveCmd.Flags().IntVarP(&serverPort, "port", "p", 8080, "server port")
 
-	_ = viper.BindPFlag("server.host", serveCmd.Flags().Lookup("host"))
-	_ = viper.BindPFlag("server.port", serveCmd.Flags().Lookup("port"))
+	bindFlag("server.host", serveCmd.Flags().Lookup("host"))
+	bindFlag("server.port", serveCmd.Flags().Lookup("port"))
 }
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+)
+
+func TestUnmarshalSectionKeepsNestedDefaults(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	viper.SetDefault("logging.redaction.enabled", true)
+	viper.SetDefault("logging.access.enabled", true)
+	viper.SetDefault("logging.access.sample_rate", 1.0)
+	viper.SetConfigType("yaml")
+	if err := viper.ReadConfig(strings.NewReader("logging:\n  level: debug\n  access:\n    slow_threshold: 250ms\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg config.LoggingConfig
+	if err := unmarshalSection("logging", &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Level != "debug" || cfg.Access.SlowThreshold != 250*time.Millisecond {
+		t.Errorf("config file values lost: %+v", cfg)
+	}
+	if !cfg.Redaction.Enabled || !cfg.Access.Enabled || cfg.Access.SampleRate != 1 {
+		t.Errorf("nested defaults lost: redaction=%+v access=%+v", cfg.Redaction, cfg.Access)
+	}
+}
+
+func TestUnmarshalSectionKeepsHealthDefaults(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	setDefaults()
+	viper.SetConfigType("yaml")
+	file := "health:\n  checks:\n    - name: db\n      type: tcp\n      address: localhost:5432\n      timeout: 1s\n"
+	if err := viper.ReadConfig(strings.NewReader(file)); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg config.HealthConfig
+	if err := unmarshalSection("health", &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cfg.Checks) != 1 || cfg.Checks[0].Address != "localhost:5432" || cfg.Checks[0].Timeout != time.Second {
+		t.Errorf("config file checks lost: %+v", cfg.Checks)
+	}
+	if !cfg.Enabled || cfg.HistorySize != 100 {
+		t.Errorf("health defaults lost: enabled=%v history_size=%d", cfg.Enabled, cfg.HistorySize)
+	}
+	if cfg.Probes.Live.Path != "/health/live" || cfg.Probes.Live.Timeout != 2*time.Second ||
+		cfg.Probes.Ready.Path != "/health/ready" || cfg.Probes.Aggregate.Timeout != 5*time.Second {
+		t.Errorf("probe defaults lost: %+v", cfg.Probes)
+	}
+}
+
+func TestUnmarshalSectionKeepsMetricsDefaults(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	setDefaults()
+	viper.SetConfigType("yaml")
+	file := "metrics:\n  push:\n    otlp:\n      enabled: true\n"
+	if err := viper.ReadConfig(strings.NewReader(file)); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg config.MetricsConfig
+	if err := unmarshalSection("metrics", &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if !cfg.Push.OTLP.Enabled {
+		t.Errorf("config file values lost: %+v", cfg.Push.OTLP)
+	}
+	if cfg.MaxLabelValues != 100 {
+		t.Errorf("max_label_values default lost: got %d", cfg.MaxLabelValues)
+	}
+	if cfg.Push.OTLP.Endpoint == "" || cfg.Push.OTLP.Interval == 0 || cfg.Push.OTLP.Timeout == 0 {
+		t.Errorf("push defaults lost: %+v", cfg.Push.OTLP)
+	}
+}
+
+func TestUnmarshalSectionDecodesSLOObjectives(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	setDefaults()
+	viper.SetConfigType("yaml")
+	file := "slo:\n  objectives:\n    - name: api-latency\n      type: latency\n      routes: [\"/items/{id}\"]\n      target: 99\n      threshold: 250ms\n"
+	if err := viper.ReadConfig(strings.NewReader(file)); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg config.SLOConfig
+	if err := unmarshalSection("slo", &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cfg.Objectives) != 1 {
+		t.Fatalf("expected one objective, got %+v", cfg.Objectives)
+	}
+	objective := cfg.Objectives[0]
+	if objective.Name != "api-latency" || objective.Threshold != 250*time.Millisecond ||
+		objective.Target != 99 || len(objective.Routes) != 1 || objective.Routes[0] != "/items/{id}" {
+		t.Errorf("objective not decoded: %+v", objective)
+	}
+}
+
+func TestUnmarshalSectionWithoutConfigFile(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	setDefaults()
+
+	var slos config.SLOConfig
+	if err := unmarshalSection("slo", &slos); err != nil || len(slos.Objectives) != 0 {
+		t.Fatalf("expected no objectives, got %+v (err %v)", slos, err)
+	}
+	var metricsCfg config.MetricsConfig
+	if err := unmarshalSection("metrics", &metricsCfg); err != nil || !metricsCfg.Enabled || metricsCfg.Port != 9090 {
+		t.Fatalf("expected metrics defaults, got %+v (err %v)", metricsCfg, err)
+	}
+}
+
+func TestAccessExcludePathsFollowProbeConfig(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	setDefaults()
+	viper.SetConfigType("yaml")
+	file := "health:\n  probes:\n    live:\n      path: /livez\n      aliases: [/healthz]\n"
+	if err := viper.ReadConfig(strings.NewReader(file)); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg config.HealthConfig
+	if err := unmarshalSection("health", &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if viper.IsSet("logging.access.exclude_paths") {
+		t.Fatal("exclude_paths should be unset without a config value")
+	}
+
+	got := accessExcludePaths(cfg)
+	want := []string{"/health", "/livez", "/healthz", "/health/ready", "/health/startup", "/metrics"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("exclude paths = %v, want %v", got, want)
+	}
+
+	cfg.Enabled = false
+	if got := accessExcludePaths(cfg); strings.Join(got, ",") != "/metrics" {
+		t.Errorf("exclude paths with health disabled = %v, want [/metrics]", got)
+	}
+}
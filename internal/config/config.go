@@ -12,6 +12,7 @@ type Config struct {
 	Logging LoggingConfig `mapstructure:"logging"`
 	Metrics MetricsConfig `mapstructure:"metrics"`
 	Health  HealthConfig  `mapstructure:"health"`
+	SLO     SLOConfig     `mapstructure:"slo"`
 	Debug   DebugConfig   `mapstructure:"debug"`
 	Workers int           `mapstructure:"workers"`
 }
@@ -40,6 +41,167 @@ type LoggingConfig struct {
 	// Valid values: SIMPLE, STRUCTURED, ENTERPRISE
 	// See: gofulmen/docs/crucible-go/standards/observability/logging.md
 	Profile string `mapstructure:"profile"`
+
+	// Environment is attached to every server log line (production, staging, ...)
+	Environment string `mapstructure:"environment"`
+
+	// Sinks lists the log outputs. Empty uses the profile default: colorized
+	// console text for SIMPLE, one JSON console sink otherwise. STRUCTURED
+	// allows one sink; ENTERPRISE allows any number.
+	Sinks []LogSinkConfig `mapstructure:"sinks"`
+
+	// Middleware processes every event before it reaches the sinks
+	// (ENTERPRISE only)
+	Middleware []LogMiddlewareConfig `mapstructure:"middleware"`
+
+	// Throttling rate-limits log events below ERROR (STRUCTURED and ENTERPRISE)
+	Throttling LogThrottlingConfig `mapstructure:"throttling"`
+
+	// Redaction masks secrets and personal data in log entries and error
+	// responses (all profiles)
+	Redaction LogRedactionConfig `mapstructure:"redaction"`
+
+	// Access controls the per-request access log
+	Access AccessLogConfig `mapstructure:"access"`
+}
+
+// LogSinkConfig configures one log output
+type LogSinkConfig struct {
+	// Type of sink: console (stderr), file, syslog, journald or http
+	Type string `mapstructure:"type"`
+
+	// Format: json, or console for human-readable text (also the message
+	// body of syslog sinks; journald sinks map fields to journal fields)
+	Format string `mapstructure:"format"`
+
+	// Level is an optional minimum for this sink on top of logging.level
+	Level string `mapstructure:"level"`
+
+	// Colorize highlights severities in console format
+	Colorize bool `mapstructure:"colorize"`
+
+	// Path of the log file (file sinks)
+	Path string `mapstructure:"path"`
+
+	// MaxSizeMB rotates the file once it reaches this size (file sinks;
+	// 0 uses 100)
+	MaxSizeMB int `mapstructure:"max_size_mb"`
+
+	// MaxAgeDays deletes rotated files older than this (0 keeps them)
+	MaxAgeDays int `mapstructure:"max_age_days"`
+
+	// MaxBackups caps the number of rotated files kept (0 keeps all)
+	MaxBackups int `mapstructure:"max_backups"`
+
+	// Compress gzips rotated files
+	Compress bool `mapstructure:"compress"`
+
+	// Address of the syslog server: unix:///dev/log, udp://host:514 or
+	// tcp://host:601 (RFC 6587 octet counting). For journald, the socket path.
+	// Empty uses the local socket.
+	Address string `mapstructure:"address"`
+
+	// Facility of syslog messages: daemon (default), user, local0-local7, ...
+	Facility string `mapstructure:"facility"`
+
+	// Endpoint is the http(s) URL http sinks POST NDJSON batches to
+	Endpoint string `mapstructure:"endpoint"`
+
+	// Headers are added to every http sink request (e.g. Authorization)
+	Headers map[string]string `mapstructure:"headers"`
+
+	// BatchSize is the number of entries per request (http sinks; 0 uses 500)
+	BatchSize int `mapstructure:"batch_size"`
+
+	// FlushInterval sends a partial batch after this long (http sinks; 0 uses 1s)
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+
+	// Timeout bounds each http sink request (0 uses 5s)
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// BufferPath is a directory where http sinks spill batches the endpoint
+	// did not accept, delivered once it is back (also after a restart).
+	// Empty drops them after the retries.
+	BufferPath string `mapstructure:"buffer_path"`
+
+	// BufferMaxMB caps the disk buffer; the oldest batches are dropped when
+	// it is full (0 uses 64)
+	BufferMaxMB int `mapstructure:"buffer_max_mb"`
+}
+
+// LogMiddlewareConfig enables a gofulmen logging middleware by registry name
+// (correlation, redact-secrets, redact-pii)
+type LogMiddlewareConfig struct {
+	Type string `mapstructure:"type"`
+
+	// Order in the pipeline; lower runs first (0 keeps the middleware default)
+	Order int `mapstructure:"order"`
+
+	// Config is passed to the middleware factory
+	Config map[string]any `mapstructure:"config"`
+}
+
+// LogThrottlingConfig caps log throughput with a token bucket; events over
+// the limit are dropped, except ERROR and above, which are never throttled
+type LogThrottlingConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+
+	// MaxRate is the sustained events per second
+	MaxRate int `mapstructure:"max_rate"`
+
+	// BurstSize is the number of events allowed above MaxRate in a burst
+	BurstSize int `mapstructure:"burst_size"`
+}
+
+// LogRedactionConfig controls redaction of sensitive data. Built-in field
+// names (password, token, secret, authorization, ...) and patterns (bearer
+// tokens, JWTs, key=value secrets, URL credentials, emails, card numbers)
+// always apply; Fields and Patterns add to them.
+type LogRedactionConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+
+	// Mode is mask (replace with [REDACTED]) or hash (replace with a short
+	// HMAC-SHA256 digest so equal values can be correlated). Error responses
+	// are always masked.
+	Mode string `mapstructure:"mode"`
+
+	// HashSecret keys hash mode digests. Set it to correlate values across
+	// restarts and instances; when empty a random key is used per process.
+	HashSecret string `mapstructure:"hash_secret"`
+
+	// Fields are extra field names whose values are always redacted. Case,
+	// '_' and '-' are ignored and names match as a suffix (token covers
+	// admin_token).
+	Fields []string `mapstructure:"fields"`
+
+	// Patterns are extra regular expressions redacted inside strings; a
+	// (?P<secret>...) group limits redaction to that part of the match
+	Patterns []string `mapstructure:"patterns"`
+}
+
+// AccessLogConfig controls which requests get an access log entry and how it
+// is written. Client (4xx) and server (5xx) errors and slow requests are
+// never sampled out.
+type AccessLogConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+
+	// ExcludePaths are request paths that are never logged; a trailing "*"
+	// matches any suffix (/health/*). When unset, serve excludes the health
+	// probe routes and /metrics.
+	ExcludePaths []string `mapstructure:"exclude_paths"`
+
+	// SampleRate is the fraction of successful requests logged, from 0 to 1
+	SampleRate float64 `mapstructure:"sample_rate"`
+
+	// SlowThreshold logs requests taking longer at WARN (0 disables)
+	SlowThreshold time.Duration `mapstructure:"slow_threshold"`
+
+	// Format is json (structured entries through the server logger), common
+	// or combined (Common/Combined Log Format lines)
+	Format string `mapstructure:"format"`
+
+	// Path is the file for common and combined lines; empty writes to stdout
+	Path string `mapstructure:"path"`
 }
 
 // MetricsConfig contains Prometheus metrics configuration
@@ -47,15 +209,197 @@ type MetricsConfig struct {
 	// Enabled controls whether metrics are exposed
 	Enabled bool `mapstructure:"enabled"`
 
-	// Port is the dedicated metrics endpoint port (Prometheus format)
-	// Metrics are also available at the main HTTP port in JSON format
+	// Port is the dedicated metrics endpoint port (Prometheus format).
+	// /metrics on the main HTTP port is always rendered in-process;
+	// 0 disables the dedicated listener.
 	Port int `mapstructure:"port"`
+
+	// Buckets overrides histogram bucket upper bounds per metric name
+	// (durations in milliseconds, sizes in bytes)
+	Buckets map[string][]float64 `mapstructure:"buckets"`
+
+	// MaxLabelValues caps distinct values per metric label; further values
+	// collapse to "__overflow__" (0 uses the default of 100, -1 disables the guard)
+	MaxLabelValues int `mapstructure:"max_label_values"`
+
+	// Push configures exporters that send metrics to a collector on an
+	// interval, for processes that are never scraped
+	Push MetricsPushConfig `mapstructure:"push"`
+}
+
+// MetricsPushConfig contains the push exporters
+type MetricsPushConfig struct {
+	// OTLP pushes to an OpenTelemetry collector over OTLP/HTTP (JSON encoding)
+	OTLP PushExporterConfig `mapstructure:"otlp"`
+
+	// StatsD sends StatsD lines over UDP
+	StatsD StatsDConfig `mapstructure:"statsd"`
+
+	// RemoteWrite pushes to a Prometheus remote-write receiver
+	RemoteWrite PushExporterConfig `mapstructure:"remote_write"`
+}
+
+// PushExporterConfig configures one push exporter
+type PushExporterConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+
+	// Endpoint is the receiver URL, or host:port for StatsD
+	Endpoint string `mapstructure:"endpoint"`
+
+	// Interval between pushes; metrics are also flushed on shutdown
+	Interval time.Duration `mapstructure:"interval"`
+
+	// Timeout bounds each push
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// Headers are added to every request (e.g. Authorization); unused by StatsD
+	Headers map[string]string `mapstructure:"headers"`
+}
+
+// StatsDConfig configures the StatsD exporter
+type StatsDConfig struct {
+	PushExporterConfig `mapstructure:",squash"`
+
+	// DogStatsD sends labels as DogStatsD tags (|#key:value); plain StatsD
+	// has no tags, so labels are dropped when false
+	DogStatsD bool `mapstructure:"dogstatsd"`
+}
+
+// SLOConfig declares service level objectives evaluated in-process from
+// the requests seen by the HTTP metrics middleware
+type SLOConfig struct {
+	Objectives []SLOObjectiveConfig `mapstructure:"objectives"`
+}
+
+// SLOObjectiveConfig describes a single objective
+type SLOObjectiveConfig struct {
+	// Name identifies the objective in reports and metric labels (must be unique)
+	Name string `mapstructure:"name"`
+
+	// Type selects the SLI
+	// Valid values: availability (non-5xx responses), latency (responses under Threshold)
+	Type string `mapstructure:"type"`
+
+	// Routes are chi route patterns (e.g. /items/{id}); empty matches every route
+	Routes []string `mapstructure:"routes"`
+
+	// Target is the percentage of good requests, e.g. 99.9
+	Target float64 `mapstructure:"target"`
+
+	// Threshold is the latency a request must beat to count as good (latency only)
+	Threshold time.Duration `mapstructure:"threshold"`
+
+	// Window is the compliance period; Go durations plus a d suffix (default 30d)
+	Window string `mapstructure:"window"`
 }
 
 // HealthConfig contains health check configuration
 type HealthConfig struct {
 	// Enabled controls whether health endpoints are exposed
 	Enabled bool `mapstructure:"enabled"`
+
+	// Probes configures the path, aliases and timeout of each health endpoint
+	Probes HealthProbesConfig `mapstructure:"probes"`
+
+	// HistorySize bounds the probe evaluations and transitions kept for /health/history
+	HistorySize int `mapstructure:"history_size"`
+
+	// Checks lists built-in dependency checks registered with the health manager
+	// See internal/health for the supported check types
+	Checks []HealthCheckConfig `mapstructure:"checks"`
+}
+
+// HealthProbesConfig groups per-probe endpoint configuration
+type HealthProbesConfig struct {
+	Aggregate HealthProbeConfig `mapstructure:"aggregate"`
+	Live      HealthProbeConfig `mapstructure:"live"`
+	Ready     HealthProbeConfig `mapstructure:"ready"`
+	Startup   HealthProbeConfig `mapstructure:"startup"`
+}
+
+// HealthProbeConfig configures a single health probe endpoint
+type HealthProbeConfig struct {
+	// Path is the primary route for the probe (empty uses the standard path)
+	Path string `mapstructure:"path"`
+
+	// Aliases are additional routes served by the same probe (e.g. /healthz, /livez, /readyz)
+	Aliases []string `mapstructure:"aliases"`
+
+	// Timeout bounds check execution for the probe (0 uses the built-in default)
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// Routes returns the probe's path, or defaultPath when unset, followed by its aliases
+func (p HealthProbeConfig) Routes(defaultPath string) []string {
+	path := p.Path
+	if path == "" {
+		path = defaultPath
+	}
+	routes := []string{path}
+	for _, alias := range p.Aliases {
+		if alias != "" {
+			routes = append(routes, alias)
+		}
+	}
+	return routes
+}
+
+// Routes returns every route the probes are served on, with the standard
+// paths for probes without one
+func (c HealthProbesConfig) Routes() []string {
+	var routes []string
+	routes = append(routes, c.Aggregate.Routes("/health")...)
+	routes = append(routes, c.Live.Routes("/health/live")...)
+	routes = append(routes, c.Ready.Routes("/health/ready")...)
+	routes = append(routes, c.Startup.Routes("/health/startup")...)
+	return routes
+}
+
+// HealthCheckConfig describes a single built-in dependency health check.
+// Only the fields relevant to the selected Type are consulted.
+type HealthCheckConfig struct {
+	// Name is the key reported in health responses (must be unique)
+	Name string `mapstructure:"name"`
+
+	// Type selects the checker implementation
+	// Valid values: tcp, http, dns, disk, file, goroutines, heap, fds
+	Type string `mapstructure:"type"`
+
+	// Timeout bounds a single check execution (0 uses the probe deadline)
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// Address is the host:port dialed by tcp checks
+	Address string `mapstructure:"address"`
+
+	// URL is fetched by http checks
+	URL string `mapstructure:"url"`
+
+	// ExpectedStatus is the HTTP status required by http checks (default 200)
+	ExpectedStatus int `mapstructure:"expected_status"`
+
+	// Host is resolved by dns checks
+	Host string `mapstructure:"host"`
+
+	// Path is inspected by disk and file checks
+	Path string `mapstructure:"path"`
+
+	// MinFreeBytes is the minimum free space required by disk checks
+	MinFreeBytes uint64 `mapstructure:"min_free_bytes"`
+
+	// MinFreePercent is the minimum free space percentage required by disk checks
+	MinFreePercent float64 `mapstructure:"min_free_percent"`
+
+	// Writable makes file checks verify write access in addition to read access
+	Writable bool `mapstructure:"writable"`
+
+	// MaxGoroutines is the goroutine count ceiling for goroutines checks
+	MaxGoroutines int `mapstructure:"max_goroutines"`
+
+	// MaxHeapBytes is the heap-in-use ceiling for heap checks
+	MaxHeapBytes uint64 `mapstructure:"max_heap_bytes"`
+
+	// MaxRatio is the open/limit file descriptor ceiling (0-1) for fds checks
+	MaxRatio float64 `mapstructure:"max_ratio"`
 }
 
 // DebugConfig contains debug and profiling configuration
@@ -65,13 +65,26 @@ func TestLoad(t *testing.T) {
 		// Verify logging defaults
 		assert.Equal(t, "info", cfg.Logging.Level)
 		assert.Equal(t, "STRUCTURED", cfg.Logging.Profile)
+		assert.Equal(t, "production", cfg.Logging.Environment)
+		assert.Empty(t, cfg.Logging.Sinks)
+		assert.False(t, cfg.Logging.Throttling.Enabled)
+		assert.Equal(t, 1000, cfg.Logging.Throttling.MaxRate)
 
 		// Verify metrics defaults
 		assert.True(t, cfg.Metrics.Enabled)
 		assert.Equal(t, 9090, cfg.Metrics.Port)
+		assert.Equal(t, 100, cfg.Metrics.MaxLabelValues)
+		assert.Equal(t, []float64{100, 1000, 10000, 100000, 1000000, 10000000}, cfg.Metrics.Buckets["http_response_size_bytes"])
+		assert.False(t, cfg.Metrics.Push.OTLP.Enabled)
+		assert.Equal(t, 15*time.Second, cfg.Metrics.Push.OTLP.Interval)
+		assert.Equal(t, "localhost:8125", cfg.Metrics.Push.StatsD.Endpoint)
+		assert.True(t, cfg.Metrics.Push.StatsD.DogStatsD)
 
 		// Verify health defaults
 		assert.True(t, cfg.Health.Enabled)
+		assert.Equal(t, "/health/live", cfg.Health.Probes.Live.Path)
+		assert.Equal(t, 2*time.Second, cfg.Health.Probes.Live.Timeout)
+		assert.Empty(t, cfg.Health.Checks)
 
 		// Verify debug defaults
 		assert.False(t, cfg.Debug.Enabled)
@@ -280,6 +280,7 @@ func withWrappedError(envelope *errors.ErrorEnvelope, err error) *errors.ErrorEn
 }
 
 // ResponseDetails constructs API-safe details map by merging envelope details and context.
+// Sensitive fields and values (including wrapped_error text) are masked.
 func ResponseDetails(envelope *errors.ErrorEnvelope) map[string]interface{} {
 	if envelope == nil {
 		return nil
@@ -301,7 +302,7 @@ func ResponseDetails(envelope *errors.ErrorEnvelope) map[string]interface{} {
 		return nil
 	}
 
-	return details
+	return observability.ResponseRedactor().Map(details)
 }
 
 // HTTPErrorDetail captures the error body returned to callers.
@@ -339,13 +340,13 @@ func RespondWithEnvelope(w http.ResponseWriter, r *http.Request, envelope *error
 	response := HTTPErrorResponse{
 		Error: HTTPErrorDetail{
 			Code:      envelope.Code,
-			Message:   envelope.Message,
+			Message:   observability.ResponseRedactor().String(envelope.Message),
 			Details:   ResponseDetails(envelope),
 			RequestID: envelope.CorrelationID,
 		},
 	}
 
-	logHTTPError(envelope, statusCode)
+	logHTTPError(r, envelope, statusCode)
 	emitErrorMetrics(r, envelope, statusCode)
 
 	w.Header().Set("Content-Type", "application/json")
@@ -353,10 +354,16 @@ func RespondWithEnvelope(w http.ResponseWriter, r *http.Request, envelope *error
 	_ = json.NewEncoder(w).Encode(response)
 }
 
-func logHTTPError(envelope *errors.ErrorEnvelope, statusCode int) {
+// logHTTPError logs through the request logger, which already carries
+// request_id, method and route. The logger redacts the context fields.
+func logHTTPError(r *http.Request, envelope *errors.ErrorEnvelope, statusCode int) {
 	if observability.ServerLogger == nil || envelope == nil {
 		return
 	}
+	logger := observability.ServerLogger
+	if r != nil {
+		logger = observability.FromContext(r.Context())
+	}
 
 	fields := []zap.Field{
 		zap.String("error_code", envelope.Code),
@@ -371,17 +378,17 @@ func logHTTPError(envelope *errors.ErrorEnvelope, statusCode int) {
 		fields = append(fields, zap.Any(key, value))
 	}
 
-	if envelope.CorrelationID != "" {
+	if r == nil && envelope.CorrelationID != "" {
 		fields = append(fields, zap.String("request_id", envelope.CorrelationID))
 	}
 
 	switch envelope.Severity {
 	case errors.SeverityCritical, errors.SeverityHigh:
-		observability.ServerLogger.Error(envelope.Message, fields...)
+		logger.Error(envelope.Message, fields...)
 	case errors.SeverityMedium:
-		observability.ServerLogger.Warn(envelope.Message, fields...)
+		logger.Warn(envelope.Message, fields...)
 	default:
-		observability.ServerLogger.Info(envelope.Message, fields...)
+		logger.Info(envelope.Message, fields...)
 	}
 }
 
@@ -392,6 +399,7 @@ func emitErrorMetrics(r *http.Request, envelope *errors.ErrorEnvelope, statusCod
 
 	metrics.RecordError(envelope.Code, statusCode)
 	if r != nil {
-		metrics.RecordErrorByEndpoint(r.URL.Path, envelope.Code)
+		// Route patterns, never raw paths, to keep label cardinality bounded
+		metrics.RecordErrorByEndpoint(middleware.EndpointPattern(r), envelope.Code)
 	}
 }
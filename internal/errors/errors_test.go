@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+func TestRespondWithErrorRedactsSecrets(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "server.log")
+	logger, err := observability.NewLogger("test", config.LoggingConfig{
+		Level:     "info",
+		Sinks:     []config.LogSinkConfig{{Type: "file", Path: logPath}},
+		Redaction: config.LogRedactionConfig{Enabled: true},
+	})
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+	original := observability.ServerLogger
+	observability.ServerLogger = logger
+	t.Cleanup(func() {
+		observability.ServerLogger = original
+		_ = logger.ReopenFiles()
+	})
+
+	secrets := []string{"dbpass99", "jane.doe@example.com", "abcdef0123456789xyz"}
+	envelope := WrapExternalService(context.Background(),
+		fmt.Errorf("dial postgres://app:%s@db:5432/app as %s", secrets[0], secrets[1]),
+		"database unavailable")
+	fields := map[string]any{"authorization": "Bearer " + secrets[2]}
+	for key, value := range envelope.Context {
+		fields[key] = value
+	}
+	envelope, _ = envelope.WithContext(fields)
+
+	rec := httptest.NewRecorder()
+	RespondWithError(rec, httptest.NewRequest(http.MethodGet, "/v1/items", nil), envelope)
+
+	logged, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, output := range map[string]string{"response": rec.Body.String(), "log": string(logged)} {
+		if !strings.Contains(output, "database unavailable") {
+			t.Fatalf("%s is missing the error: %s", name, output)
+		}
+		for _, secret := range secrets {
+			if strings.Contains(output, secret) {
+				t.Errorf("%s contains %q: %s", name, secret, output)
+			}
+		}
+	}
+	if !strings.Contains(rec.Body.String(), `"wrapped_error":"dial postgres://app:[REDACTED]@db:5432/app`) {
+		t.Errorf("wrapped_error should keep its non-secret text: %s", rec.Body.String())
+	}
+}
+
+func TestResponseDetailsRedactsBeforeLoggerInit(t *testing.T) {
+	original := observability.ServerLogger
+	observability.ServerLogger = nil
+	t.Cleanup(func() { observability.ServerLogger = original })
+
+	envelope, _ := NewInternalError("boom").WithContext(map[string]any{"api_key": "k-123"})
+	if got := ResponseDetails(envelope)["api_key"]; got != observability.RedactedValue {
+		t.Errorf("api_key = %v, want redacted", got)
+	}
+}
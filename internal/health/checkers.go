@@ -0,0 +1,234 @@
+// Package health provides ready-made HealthChecker implementations for common
+// dependencies (network endpoints, DNS, disk, files) and process resources
+// (goroutines, heap, file descriptors). Checkers can be constructed directly or
+// built from the health.checks list in the application config.
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+)
+
+// withTimeout derives a context bounded by timeout when one is configured
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// TCPChecker verifies that a TCP connection can be established to Address
+type TCPChecker struct {
+	Address string
+	Timeout time.Duration
+}
+
+// CheckHealth dials the configured address and closes the connection
+func (c TCPChecker) CheckHealth(ctx context.Context) error {
+	ctx, cancel := withTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.Address)
+	if err != nil {
+		return fmt.Errorf("tcp dial %s: %w", c.Address, err)
+	}
+	_ = conn.Close()
+	return nil
+}
+
+// HTTPChecker verifies that a GET request to URL returns ExpectedStatus
+type HTTPChecker struct {
+	URL            string
+	ExpectedStatus int
+	Timeout        time.Duration
+
+	// Client is used for requests (defaults to http.DefaultClient)
+	Client *http.Client
+}
+
+// CheckHealth issues a GET request and compares the response status
+func (c HTTPChecker) CheckHealth(ctx context.Context) error {
+	ctx, cancel := withTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL, nil)
+	if err != nil {
+		return fmt.Errorf("http request %s: %w", c.URL, err)
+	}
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("http get %s: %w", c.URL, err)
+	}
+	_ = resp.Body.Close()
+
+	expected := c.ExpectedStatus
+	if expected == 0 {
+		expected = http.StatusOK
+	}
+	if resp.StatusCode != expected {
+		return fmt.Errorf("http get %s: expected status %d, got %d", c.URL, expected, resp.StatusCode)
+	}
+	return nil
+}
+
+// DNSChecker verifies that Host resolves to at least one address
+type DNSChecker struct {
+	Host    string
+	Timeout time.Duration
+
+	// Resolver is used for lookups (defaults to net.DefaultResolver)
+	Resolver *net.Resolver
+}
+
+// CheckHealth resolves the configured host
+func (c DNSChecker) CheckHealth(ctx context.Context) error {
+	ctx, cancel := withTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	resolver := c.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	addrs, err := resolver.LookupHost(ctx, c.Host)
+	if err != nil {
+		return fmt.Errorf("dns lookup %s: %w", c.Host, err)
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("dns lookup %s: no addresses returned", c.Host)
+	}
+	return nil
+}
+
+// DiskChecker verifies that the filesystem holding Path has enough free space.
+// Either or both of MinFreeBytes and MinFreePercent may be set.
+type DiskChecker struct {
+	Path           string
+	MinFreeBytes   uint64
+	MinFreePercent float64
+}
+
+// CheckHealth compares available space on the filesystem against the thresholds
+func (c DiskChecker) CheckHealth(ctx context.Context) error {
+	free, total, err := diskSpace(c.Path)
+	if err != nil {
+		return fmt.Errorf("disk stat %s: %w", c.Path, err)
+	}
+
+	if c.MinFreeBytes > 0 && free < c.MinFreeBytes {
+		return fmt.Errorf("disk %s: %d bytes free, need %d", c.Path, free, c.MinFreeBytes)
+	}
+	if c.MinFreePercent > 0 && total > 0 {
+		percent := float64(free) / float64(total) * 100
+		if percent < c.MinFreePercent {
+			return fmt.Errorf("disk %s: %.1f%% free, need %.1f%%", c.Path, percent, c.MinFreePercent)
+		}
+	}
+	return nil
+}
+
+// FileChecker verifies that Path is readable and, optionally, writable.
+// Directories are checked by listing them and, when Writable is set, by
+// creating and removing a temporary file inside them.
+type FileChecker struct {
+	Path     string
+	Writable bool
+}
+
+// CheckHealth probes the configured path for the requested access
+func (c FileChecker) CheckHealth(ctx context.Context) error {
+	info, err := os.Stat(c.Path)
+	if err != nil {
+		return fmt.Errorf("file stat %s: %w", c.Path, err)
+	}
+
+	f, err := os.Open(c.Path)
+	if err != nil {
+		return fmt.Errorf("file %s not readable: %w", c.Path, err)
+	}
+	_ = f.Close()
+
+	if !c.Writable {
+		return nil
+	}
+
+	if info.IsDir() {
+		tmp, err := os.CreateTemp(c.Path, ".healthcheck-*")
+		if err != nil {
+			return fmt.Errorf("directory %s not writable: %w", c.Path, err)
+		}
+		name := tmp.Name()
+		_ = tmp.Close()
+		_ = os.Remove(name)
+		return nil
+	}
+
+	f, err = os.OpenFile(c.Path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return fmt.Errorf("file %s not writable: %w", c.Path, err)
+	}
+	_ = f.Close()
+	return nil
+}
+
+// GoroutineChecker fails when the number of goroutines exceeds Max
+type GoroutineChecker struct {
+	Max int
+}
+
+// CheckHealth compares the live goroutine count against the ceiling
+func (c GoroutineChecker) CheckHealth(ctx context.Context) error {
+	if n := runtime.NumGoroutine(); n > c.Max {
+		return fmt.Errorf("goroutines: %d running, ceiling %d", n, c.Max)
+	}
+	return nil
+}
+
+// HeapChecker fails when heap memory in use exceeds MaxBytes
+type HeapChecker struct {
+	MaxBytes uint64
+}
+
+// CheckHealth compares HeapInuse against the ceiling
+func (c HeapChecker) CheckHealth(ctx context.Context) error {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	if stats.HeapInuse > c.MaxBytes {
+		return fmt.Errorf("heap: %d bytes in use, ceiling %d", stats.HeapInuse, c.MaxBytes)
+	}
+	return nil
+}
+
+// FDChecker fails when the ratio of open file descriptors to the process
+// limit exceeds MaxRatio
+type FDChecker struct {
+	MaxRatio float64
+}
+
+// CheckHealth compares open descriptors against the soft limit
+func (c FDChecker) CheckHealth(ctx context.Context) error {
+	open, limit, err := fileDescriptors()
+	if err != nil {
+		return fmt.Errorf("file descriptors: %w", err)
+	}
+	if limit == 0 {
+		return nil
+	}
+	ratio := float64(open) / float64(limit)
+	if ratio > c.MaxRatio {
+		return fmt.Errorf("file descriptors: %d of %d open (%.2f), ceiling %.2f", open, limit, ratio, c.MaxRatio)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package health
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+)
+
+func TestTCPChecker(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("loopback listen not permitted: %v", err)
+	}
+	addr := ln.Addr().String()
+
+	assert.NoError(t, TCPChecker{Address: addr}.CheckHealth(context.Background()))
+
+	require.NoError(t, ln.Close())
+	assert.Error(t, TCPChecker{Address: addr}.CheckHealth(context.Background()))
+}
+
+func TestHTTPChecker(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/teapot" {
+			w.WriteHeader(http.StatusTeapot)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	assert.NoError(t, HTTPChecker{URL: srv.URL}.CheckHealth(ctx))
+	assert.Error(t, HTTPChecker{URL: srv.URL + "/teapot"}.CheckHealth(ctx))
+	assert.NoError(t, HTTPChecker{URL: srv.URL + "/teapot", ExpectedStatus: http.StatusTeapot}.CheckHealth(ctx))
+}
+
+func TestFileChecker(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.txt")
+	require.NoError(t, os.WriteFile(path, []byte("ok"), 0o600))
+
+	ctx := context.Background()
+	assert.NoError(t, FileChecker{Path: path, Writable: true}.CheckHealth(ctx))
+	assert.NoError(t, FileChecker{Path: dir, Writable: true}.CheckHealth(ctx))
+	assert.Error(t, FileChecker{Path: filepath.Join(dir, "missing")}.CheckHealth(ctx))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "writable directory probe should clean up after itself")
+}
+
+func TestResourceCheckers(t *testing.T) {
+	ctx := context.Background()
+
+	assert.NoError(t, GoroutineChecker{Max: 1 << 20}.CheckHealth(ctx))
+	assert.Error(t, GoroutineChecker{Max: 0}.CheckHealth(ctx))
+
+	assert.NoError(t, HeapChecker{MaxBytes: 1 << 40}.CheckHealth(ctx))
+	assert.Error(t, HeapChecker{MaxBytes: 1}.CheckHealth(ctx))
+}
+
+func TestSystemCheckers(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("disk and fd checks are only implemented on linux and darwin")
+	}
+	ctx := context.Background()
+
+	assert.NoError(t, DiskChecker{Path: t.TempDir(), MinFreeBytes: 1}.CheckHealth(ctx))
+	assert.Error(t, DiskChecker{Path: t.TempDir(), MinFreeBytes: 1 << 62}.CheckHealth(ctx))
+
+	assert.NoError(t, FDChecker{MaxRatio: 1}.CheckHealth(ctx))
+}
+
+func TestFromConfig(t *testing.T) {
+	t.Run("BuildsEachType", func(t *testing.T) {
+		checkers, err := FromConfig([]config.HealthCheckConfig{
+			{Name: "db", Type: "tcp", Address: "localhost:5432"},
+			{Name: "api", Type: "HTTP", URL: "http://localhost/health"},
+			{Name: "dns", Type: "dns", Host: "localhost"},
+			{Name: "disk", Type: "disk", Path: "/", MinFreePercent: 5},
+			{Name: "file", Type: "file", Path: "/tmp"},
+			{Name: "goroutines", Type: "goroutines", MaxGoroutines: 1000},
+			{Name: "heap", Type: "heap", MaxHeapBytes: 1 << 30},
+			{Name: "fds", Type: "fds", MaxRatio: 0.8},
+		})
+		require.NoError(t, err)
+		assert.Len(t, checkers, 8)
+		assert.IsType(t, HTTPChecker{}, checkers["api"])
+	})
+
+	t.Run("RejectsDuplicateNames", func(t *testing.T) {
+		_, err := FromConfig([]config.HealthCheckConfig{
+			{Name: "db", Type: "tcp", Address: "a:1"},
+			{Name: "db", Type: "tcp", Address: "b:1"},
+		})
+		assert.ErrorContains(t, err, "duplicate")
+	})
+
+	t.Run("RejectsMissingFields", func(t *testing.T) {
+		_, err := FromConfig([]config.HealthCheckConfig{{Name: "db", Type: "tcp"}})
+		assert.ErrorContains(t, err, "address")
+	})
+
+	t.Run("RejectsUnknownType", func(t *testing.T) {
+		_, err := FromConfig([]config.HealthCheckConfig{{Name: "x", Type: "carrier-pigeon"}})
+		assert.ErrorContains(t, err, "unknown type")
+	})
+}
@@ -0,0 +1,93 @@
+package health
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/server/handlers"
+)
+
+// Supported check types for health.checks entries
+const (
+	TypeTCP        = "tcp"
+	TypeHTTP       = "http"
+	TypeDNS        = "dns"
+	TypeDisk       = "disk"
+	TypeFile       = "file"
+	TypeGoroutines = "goroutines"
+	TypeHeap       = "heap"
+	TypeFDs        = "fds"
+)
+
+// NewChecker builds a HealthChecker from a single health.checks entry
+func NewChecker(cfg config.HealthCheckConfig) (handlers.HealthChecker, error) {
+	switch strings.ToLower(cfg.Type) {
+	case TypeTCP:
+		if cfg.Address == "" {
+			return nil, fmt.Errorf("check %q: tcp requires address", cfg.Name)
+		}
+		return TCPChecker{Address: cfg.Address, Timeout: cfg.Timeout}, nil
+	case TypeHTTP:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("check %q: http requires url", cfg.Name)
+		}
+		return HTTPChecker{URL: cfg.URL, ExpectedStatus: cfg.ExpectedStatus, Timeout: cfg.Timeout}, nil
+	case TypeDNS:
+		if cfg.Host == "" {
+			return nil, fmt.Errorf("check %q: dns requires host", cfg.Name)
+		}
+		return DNSChecker{Host: cfg.Host, Timeout: cfg.Timeout}, nil
+	case TypeDisk:
+		if cfg.Path == "" {
+			return nil, fmt.Errorf("check %q: disk requires path", cfg.Name)
+		}
+		if cfg.MinFreeBytes == 0 && cfg.MinFreePercent == 0 {
+			return nil, fmt.Errorf("check %q: disk requires min_free_bytes or min_free_percent", cfg.Name)
+		}
+		return DiskChecker{Path: cfg.Path, MinFreeBytes: cfg.MinFreeBytes, MinFreePercent: cfg.MinFreePercent}, nil
+	case TypeFile:
+		if cfg.Path == "" {
+			return nil, fmt.Errorf("check %q: file requires path", cfg.Name)
+		}
+		return FileChecker{Path: cfg.Path, Writable: cfg.Writable}, nil
+	case TypeGoroutines:
+		if cfg.MaxGoroutines <= 0 {
+			return nil, fmt.Errorf("check %q: goroutines requires max_goroutines", cfg.Name)
+		}
+		return GoroutineChecker{Max: cfg.MaxGoroutines}, nil
+	case TypeHeap:
+		if cfg.MaxHeapBytes == 0 {
+			return nil, fmt.Errorf("check %q: heap requires max_heap_bytes", cfg.Name)
+		}
+		return HeapChecker{MaxBytes: cfg.MaxHeapBytes}, nil
+	case TypeFDs:
+		if cfg.MaxRatio <= 0 || cfg.MaxRatio > 1 {
+			return nil, fmt.Errorf("check %q: fds requires max_ratio in (0, 1]", cfg.Name)
+		}
+		return FDChecker{MaxRatio: cfg.MaxRatio}, nil
+	default:
+		return nil, fmt.Errorf("check %q: unknown type %q", cfg.Name, cfg.Type)
+	}
+}
+
+// FromConfig builds named checkers for every health.checks entry.
+// Names must be non-empty and unique.
+func FromConfig(checks []config.HealthCheckConfig) (map[string]handlers.HealthChecker, error) {
+	checkers := make(map[string]handlers.HealthChecker, len(checks))
+	for _, cfg := range checks {
+		if cfg.Name == "" {
+			return nil, fmt.Errorf("health check of type %q is missing a name", cfg.Type)
+		}
+		if _, exists := checkers[cfg.Name]; exists {
+			return nil, fmt.Errorf("duplicate health check name %q", cfg.Name)
+		}
+
+		checker, err := NewChecker(cfg)
+		if err != nil {
+			return nil, err
+		}
+		checkers[cfg.Name] = checker
+	}
+	return checkers, nil
+}
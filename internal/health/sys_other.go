@@ -0,0 +1,17 @@
+//go:build !linux && !darwin
+
+package health
+
+import "errors"
+
+var errUnsupported = errors.New("not supported on this platform")
+
+// diskSpace is not implemented on this platform
+func diskSpace(path string) (free, total uint64, err error) {
+	return 0, 0, errUnsupported
+}
+
+// fileDescriptors is not implemented on this platform
+func fileDescriptors() (open, limit uint64, err error) {
+	return 0, 0, errUnsupported
+}
@@ -0,0 +1,40 @@
+//go:build linux || darwin
+
+package health
+
+import (
+	"os"
+	"syscall"
+)
+
+// diskSpace returns available and total bytes for the filesystem holding path
+func diskSpace(path string) (free, total uint64, err error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+	bsize := uint64(stat.Bsize)
+	return stat.Bavail * bsize, stat.Blocks * bsize, nil
+}
+
+// fileDescriptors returns the number of open descriptors and the soft limit
+func fileDescriptors() (open, limit uint64, err error) {
+	var rlim syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rlim); err != nil {
+		return 0, 0, err
+	}
+
+	// Linux exposes /proc/self/fd; macOS exposes /dev/fd
+	var entries []os.DirEntry
+	for _, dir := range []string{"/proc/self/fd", "/dev/fd"} {
+		entries, err = os.ReadDir(dir)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return uint64(len(entries)), rlim.Cur, nil
+}
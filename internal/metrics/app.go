@@ -6,6 +6,25 @@ import (
 	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
 )
 
+// HTTP request metrics, recorded by the server middleware
+var (
+	HTTPRequestsTotal     = "http_requests_total"
+	HTTPErrorsTotal       = "http_errors_total"
+	HTTPRequestDuration   = "http_request_duration_ms"
+	HTTPRequestSizeBytes  = "http_request_size_bytes"
+	HTTPResponseSizeBytes = "http_response_size_bytes"
+)
+
+// SLO metrics, refreshed by internal/slo on every scrape
+var (
+	SLOTargetRatio               = "slo_target_ratio"
+	SLOSLIRatio                  = "slo_sli_ratio"
+	SLOErrorBudgetRemainingRatio = "slo_error_budget_remaining_ratio"
+	SLOBurnRate                  = "slo_burn_rate"
+	SLOAlertFiring               = "slo_alert_firing"
+	SLOWindowRequests            = "slo_window_requests"
+)
+
 // Application-level metrics following Prometheus conventions
 var (
 	// Operations metrics
@@ -14,10 +33,14 @@ var (
 
 	// Connection metrics
 	ActiveConnections = "app_active_connections"
+	OpenConnections   = "app_open_connections"
+	IdleConnections   = "app_idle_connections"
+	RequestsInFlight  = "http_requests_in_flight"
 
 	// Health check metrics
 	HealthCheckTotal    = "app_health_check_total"
 	HealthCheckDuration = "app_health_check_duration_ms"
+	HealthStatus        = "app_health_status"
 
 	// Server lifecycle metrics
 	ServerStartTime = "app_server_start_time_seconds"
@@ -68,6 +91,39 @@ func SetActiveConnections(count int64) {
 	}
 }
 
+// SetOpenConnections sets the number of open client connections (new, active or idle)
+func SetOpenConnections(count int64) {
+	if observability.TelemetrySystem != nil {
+		_ = observability.TelemetrySystem.Gauge(
+			OpenConnections,
+			float64(count),
+			nil,
+		)
+	}
+}
+
+// SetIdleConnections sets the number of idle keep-alive connections
+func SetIdleConnections(count int64) {
+	if observability.TelemetrySystem != nil {
+		_ = observability.TelemetrySystem.Gauge(
+			IdleConnections,
+			float64(count),
+			nil,
+		)
+	}
+}
+
+// SetRequestsInFlight sets the number of HTTP requests currently being served
+func SetRequestsInFlight(count int64) {
+	if observability.TelemetrySystem != nil {
+		_ = observability.TelemetrySystem.Gauge(
+			RequestsInFlight,
+			float64(count),
+			nil,
+		)
+	}
+}
+
 // RecordHealthCheck records a health check execution
 func RecordHealthCheck(checkName string, healthy bool, duration time.Duration) {
 	status := "healthy"
@@ -95,6 +151,40 @@ func RecordHealthCheck(checkName string, healthy bool, duration time.Duration) {
 	}
 }
 
+// RecordHealthCheckTimeout records a health check that was skipped because
+// the probe deadline had already passed
+func RecordHealthCheckTimeout(checkName string) {
+	if observability.TelemetrySystem != nil {
+		_ = observability.TelemetrySystem.Counter(
+			HealthCheckTotal,
+			1,
+			map[string]string{
+				"check":  checkName,
+				"status": "timeout",
+			},
+		)
+	}
+}
+
+// SetHealthStatus records the latest outcome of a check for a probe (1 healthy, 0 otherwise)
+func SetHealthStatus(checkName string, probe string, healthy bool) {
+	value := 0.0
+	if healthy {
+		value = 1
+	}
+
+	if observability.TelemetrySystem != nil {
+		_ = observability.TelemetrySystem.Gauge(
+			HealthStatus,
+			value,
+			map[string]string{
+				"check": checkName,
+				"probe": probe,
+			},
+		)
+	}
+}
+
 // SetServerStartTime records the server start time (Unix timestamp)
 func SetServerStartTime(timestamp int64) {
 	if observability.TelemetrySystem != nil {
@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"github.com/fulmenhq/gofulmen/telemetry"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+// Definition describes one metric the service emits. The catalog is the
+// source for `groningen metrics catalog`, the generated dashboard and alert
+// rules, and docs/metrics-catalog.md; add an entry whenever a metric is added.
+type Definition struct {
+	// Name is the metric name before the namespace prefix is applied
+	Name string `json:"name"`
+
+	Type telemetry.MetricType `json:"type"`
+
+	// Unit is the unit of the exported value, which matches the name suffix
+	// (`_ms` in milliseconds, `_seconds` in seconds)
+	Unit string `json:"unit,omitempty"`
+
+	Labels []string `json:"labels"`
+	Help   string   `json:"help"`
+
+	// Group is the catalog section (http, application, ...)
+	Group string `json:"group"`
+}
+
+// Catalog groups in display order
+const (
+	GroupHTTP        = "http"
+	GroupApplication = "application"
+	GroupErrors      = "errors"
+	GroupSLO         = "slo"
+	GroupRuntime     = "runtime"
+	GroupProcess     = "process"
+	GroupTelemetry   = "telemetry"
+)
+
+// catalogGroups orders the sections of the catalog, with their display titles
+var catalogGroups = []struct{ name, title string }{
+	{GroupHTTP, "HTTP"},
+	{GroupApplication, "Application"},
+	{GroupErrors, "Errors"},
+	{GroupSLO, "SLO"},
+	{GroupRuntime, "Go Runtime"},
+	{GroupProcess, "Process"},
+	{GroupTelemetry, "Telemetry Pipeline"},
+}
+
+// catalogDefinitions lists the metrics group by group; within a group the
+// most useful metrics come first, which is also the dashboard panel order
+func catalogDefinitions() []Definition {
+	counter, gauge, histogram := telemetry.TypeCounter, telemetry.TypeGauge, telemetry.TypeHistogram
+
+	return []Definition{
+		{HTTPRequestsTotal, counter, "", []string{"method", "endpoint", "status"}, "HTTP requests served", GroupHTTP},
+		{HTTPErrorsTotal, counter, "", []string{"method", "endpoint", "status", "error_type"}, "HTTP requests that ended in a 4xx or 5xx response", GroupHTTP},
+		{HTTPRequestDuration, histogram, "milliseconds", []string{"method", "endpoint", "status"}, "HTTP request latency", GroupHTTP},
+		{HTTPRequestSizeBytes, histogram, "bytes", []string{"method", "endpoint"}, "HTTP request body size", GroupHTTP},
+		{HTTPResponseSizeBytes, histogram, "bytes", []string{"method", "endpoint"}, "HTTP response body size", GroupHTTP},
+		{RequestsInFlight, gauge, "", nil, "HTTP requests currently being served", GroupHTTP},
+
+		{OperationsTotal, counter, "", []string{"operation", "status"}, "Application operations by outcome", GroupApplication},
+		{OperationsErrorsTotal, counter, "", []string{"operation", "error_type"}, "Failed application operations by error type", GroupApplication},
+		{ActiveConnections, gauge, "", nil, "Client connections currently serving a request", GroupApplication},
+		{OpenConnections, gauge, "", nil, "Open client connections (new, active or idle)", GroupApplication},
+		{IdleConnections, gauge, "", nil, "Idle keep-alive connections", GroupApplication},
+		{HealthCheckTotal, counter, "", []string{"check", "status"}, "Health check executions by outcome", GroupApplication},
+		{HealthCheckDuration, histogram, "milliseconds", []string{"check"}, "Health check latency", GroupApplication},
+		{HealthStatus, gauge, "", []string{"check", "probe"}, "Latest health check outcome per probe (1 healthy, 0 otherwise)", GroupApplication},
+		{ServerStartTime, gauge, "timestamp", nil, "Server start time in Unix seconds", GroupApplication},
+		{ServerUptime, gauge, "seconds", nil, "Seconds since the server started", GroupApplication},
+		{BuildInfo, gauge, "", []string{"version", "commit", "go_version", "gofulmen", "crucible"}, "Constant 1; labels carry the build and dependency versions", GroupApplication},
+
+		{ErrorsTotalName, counter, "", []string{"error_code", "http_status"}, "Error responses by error code", GroupErrors},
+		{ErrorsByEndpointName, counter, "", []string{"endpoint", "error_code"}, "Error responses by route pattern", GroupErrors},
+		{PanicsTotalName, counter, "", nil, "Panics recovered by the server", GroupErrors},
+
+		{SLOTargetRatio, gauge, "ratio", []string{"slo"}, "Objective target (0.999 for 99.9%)", GroupSLO},
+		{SLOSLIRatio, gauge, "ratio", []string{"slo"}, "Good requests / total over the SLO window", GroupSLO},
+		{SLOErrorBudgetRemainingRatio, gauge, "ratio", []string{"slo"}, "Error budget left (1 untouched, 0 exhausted, negative overspent)", GroupSLO},
+		{SLOBurnRate, gauge, "", []string{"slo", "window"}, "Error rate over the window divided by the error budget", GroupSLO},
+		{SLOAlertFiring, gauge, "", []string{"slo", "severity"}, "1 while the page or ticket burn rate condition holds", GroupSLO},
+		{SLOWindowRequests, gauge, "", []string{"slo", "result"}, "Good and bad requests in the SLO window", GroupSLO},
+
+		{GoGoroutines, gauge, "", nil, "Current number of goroutines", GroupRuntime},
+		{GoMaxProcs, gauge, "", nil, "Current GOMAXPROCS setting", GroupRuntime},
+		{GoHeapObjects, gauge, "", nil, "Live and unswept heap objects", GroupRuntime},
+		{GoHeapObjectsBytes, gauge, "bytes", nil, "Memory occupied by heap objects", GroupRuntime},
+		{GoHeapGoalBytes, gauge, "bytes", nil, "Heap size target for the end of the GC cycle", GroupRuntime},
+		{GoMemoryTotalBytes, gauge, "bytes", nil, "All memory mapped by the Go runtime", GroupRuntime},
+		{GoGCCyclesTotal, counter, "", nil, "Completed GC cycles", GroupRuntime},
+		{GoHeapAllocsTotal, counter, "bytes", nil, "Cumulative bytes allocated to the heap", GroupRuntime},
+		{GoGCPauseSeconds, histogram, "seconds", nil, "Stop-the-world GC pause durations", GroupRuntime},
+		{GoSchedLatencySeconds, histogram, "seconds", nil, "Time goroutines spent runnable before running", GroupRuntime},
+
+		{ProcessCPUSecondsTotal, counter, "seconds", nil, "User and system CPU time (Linux only)", GroupProcess},
+		{ProcessResidentMemoryBytes, gauge, "bytes", nil, "Resident set size (Linux only)", GroupProcess},
+		{ProcessOpenFDs, gauge, "", nil, "Open file descriptors (Linux only)", GroupProcess},
+		{ProcessMaxFDs, gauge, "", nil, "File descriptor soft limit (Linux only)", GroupProcess},
+		{ProcessThreads, gauge, "", nil, "OS threads (Linux only)", GroupProcess},
+
+		{observability.CardinalityOverflowMetric, counter, "", []string{"metric", "label"}, "Label values folded into the overflow value by the cardinality guard", GroupTelemetry},
+		{observability.PushErrorsMetric, counter, "", []string{"exporter"}, "Failed metric pushes per exporter", GroupTelemetry},
+		{observability.LogEventsDroppedMetric, counter, "", []string{"severity"}, "Log events dropped by logging.throttling", GroupTelemetry},
+	}
+}
+
+// Catalog returns every metric definition, grouped in catalogGroups order
+func Catalog() []Definition {
+	return catalogDefinitions()
+}
+
+// Lookup returns the definition for name
+func Lookup(name string) (Definition, bool) {
+	for _, def := range catalogDefinitions() {
+		if def.Name == name {
+			return def, true
+		}
+	}
+	return Definition{}, false
+}
+
+// groupTitle returns the display title of a catalog group
+func groupTitle(group string) string {
+	for _, g := range catalogGroups {
+		if g.name == group {
+			return g.title
+		}
+	}
+	return group
+}
@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Catalog output formats
+const (
+	CatalogFormatTable    = "table"
+	CatalogFormatJSON     = "json"
+	CatalogFormatMarkdown = "markdown"
+)
+
+// WriteCatalog renders the catalog in format. namespace is the prefix the
+// exposition applies to every name; it is only shown, never added to names.
+func WriteCatalog(w io.Writer, format, namespace string) error {
+	switch format {
+	case CatalogFormatTable:
+		return writeCatalogTable(w)
+	case CatalogFormatJSON:
+		return writeCatalogJSON(w)
+	case CatalogFormatMarkdown:
+		return writeCatalogMarkdown(w, namespace)
+	default:
+		return fmt.Errorf("unknown catalog format %q (want %s, %s or %s)",
+			format, CatalogFormatTable, CatalogFormatJSON, CatalogFormatMarkdown)
+	}
+}
+
+func writeCatalogTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "GROUP\tMETRIC\tTYPE\tUNIT\tLABELS\tDESCRIPTION")
+	for _, def := range Catalog() {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			def.Group, def.Name, def.Type, orDash(def.Unit), orDash(strings.Join(def.Labels, ",")), def.Help)
+	}
+	return tw.Flush()
+}
+
+func writeCatalogJSON(w io.Writer) error {
+	defs := Catalog()
+	for i := range defs {
+		if defs[i].Labels == nil {
+			defs[i].Labels = []string{}
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(defs)
+}
+
+func writeCatalogMarkdown(w io.Writer, namespace string) error {
+	var b strings.Builder
+	b.WriteString("# Metrics Catalog\n\n")
+	b.WriteString("<!-- Generated by `groningen metrics catalog --format markdown`; run `make metrics-catalog` instead of editing. -->\n\n")
+	if namespace != "" {
+		fmt.Fprintf(&b, "Every name is exposed with the `%s_` prefix. ", namespace)
+	}
+	b.WriteString("Histogram values match their name: `_ms` histograms are in milliseconds and `_seconds` ones in seconds. See [metrics.md](metrics.md) for queries and configuration.\n")
+
+	group := ""
+	for _, def := range Catalog() {
+		if def.Group != group {
+			group = def.Group
+			fmt.Fprintf(&b, "\n## %s\n\n", groupTitle(group))
+			b.WriteString("| Metric | Type | Unit | Labels | Description |\n")
+			b.WriteString("| --- | --- | --- | --- | --- |\n")
+		}
+
+		labels := make([]string, len(def.Labels))
+		for i, label := range def.Labels {
+			labels[i] = "`" + label + "`"
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s |\n",
+			def.Name, def.Type, orDash(def.Unit), orDash(strings.Join(labels, ", ")), strings.ReplaceAll(def.Help, "|", `\|`))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
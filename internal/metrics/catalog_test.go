@@ -0,0 +1,159 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/fulmenhq/gofulmen/telemetry"
+	telemetrytesting "github.com/fulmenhq/gofulmen/telemetry/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogIsWellFormed(t *testing.T) {
+	seen := make(map[string]bool)
+	groupIndex := -1
+	for _, def := range Catalog() {
+		assert.False(t, seen[def.Name], "%s is listed twice", def.Name)
+		seen[def.Name] = true
+
+		assert.Contains(t, []telemetry.MetricType{telemetry.TypeCounter, telemetry.TypeGauge, telemetry.TypeHistogram}, def.Type, def.Name)
+		assert.NotEmpty(t, def.Help, def.Name)
+
+		index := -1
+		for i, group := range catalogGroups {
+			if group.name == def.Group {
+				index = i
+			}
+		}
+		require.NotEqual(t, -1, index, "%s has unknown group %q", def.Name, def.Group)
+		assert.GreaterOrEqual(t, index, groupIndex, "%s is out of group order", def.Name)
+		groupIndex = index
+	}
+
+	def, ok := Lookup(HTTPRequestsTotal)
+	require.True(t, ok)
+	assert.Equal(t, telemetry.TypeCounter, def.Type)
+	_, ok = Lookup("not_a_metric")
+	assert.False(t, ok)
+}
+
+// TestCatalogCoversEmittedMetrics fails when a helper emits a metric, or a
+// label, that the catalog does not describe
+func TestCatalogCoversEmittedMetrics(t *testing.T) {
+	collector := setupTelemetry(t)
+
+	RecordOperation("sync", true)
+	RecordOperationError("sync", "timeout")
+	SetActiveConnections(1)
+	SetOpenConnections(2)
+	SetIdleConnections(1)
+	SetRequestsInFlight(1)
+	RecordHealthCheck("disk", true, time.Millisecond)
+	SetHealthStatus("disk", "ready", true)
+	SetServerStartTime(time.Now().Unix())
+	SetServerUptime(5)
+	SetBuildInfo("1.0.0", "abc")
+	RecordError("NOT_FOUND", 404)
+	RecordErrorByEndpoint("/version", "NOT_FOUND")
+	RecordPanic()
+	NewRuntimeCollector().Collect()
+	NewProcessCollector().Collect()
+
+	assertCataloged(t, collector)
+}
+
+func assertCataloged(t *testing.T, collector *telemetrytesting.FakeCollector) {
+	t.Helper()
+
+	require.NotEmpty(t, collector.GetMetrics())
+	for _, m := range collector.GetMetrics() {
+		def, ok := Lookup(m.Name)
+		if !assert.True(t, ok, "%s is emitted but not in the catalog", m.Name) {
+			continue
+		}
+		assert.Equal(t, string(def.Type), string(m.Type), m.Name)
+
+		var labels []string
+		for label := range m.Tags {
+			labels = append(labels, label)
+		}
+		want := append([]string(nil), def.Labels...)
+		sort.Strings(labels)
+		sort.Strings(want)
+		assert.Equal(t, want, labels, "%s labels", m.Name)
+	}
+}
+
+func TestWriteCatalog(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteCatalog(&buf, CatalogFormatJSON, "groningen"))
+
+	var defs []Definition
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &defs))
+	assert.Len(t, defs, len(Catalog()))
+
+	buf.Reset()
+	require.NoError(t, WriteCatalog(&buf, CatalogFormatTable, "groningen"))
+	assert.Contains(t, buf.String(), HTTPRequestDuration)
+
+	assert.Error(t, WriteCatalog(&buf, "xml", "groningen"))
+}
+
+func TestGrafanaDashboard(t *testing.T) {
+	out, err := GrafanaDashboard("groningen")
+	require.NoError(t, err)
+
+	var dashboard grafanaDashboard
+	require.NoError(t, json.Unmarshal(out, &dashboard))
+	assert.Len(t, dashboard.Panels, len(Catalog())+len(catalogGroups), "one row per group and one panel per metric")
+
+	exprs := make(map[string]string)
+	for _, panel := range dashboard.Panels {
+		if len(panel.Targets) > 0 {
+			exprs[panel.Title] = panel.Targets[0].Expr
+		}
+	}
+	assert.Equal(t, "sum by (method, endpoint, status) (rate(groningen_http_requests_total[$__rate_interval]))", exprs["http_requests_total"])
+	assert.Equal(t, "histogram_quantile(0.95, sum by (le, check) (rate(groningen_app_health_check_duration_ms_bucket[$__rate_interval])))", exprs["app_health_check_duration_ms p95"])
+	assert.Equal(t, "groningen_go_goroutines", exprs["go_goroutines"])
+}
+
+func TestAlertRulesUseNamespacedNames(t *testing.T) {
+	rules := string(AlertRules("my-service"))
+
+	assert.Contains(t, rules, "- alert: MyServiceHighErrorRate")
+	assert.Contains(t, rules, `expr: "min by (check) (my_service_app_health_status{probe=\"ready\"}) == 0"`)
+	for _, rule := range alertRules {
+		for _, name := range rule.metrics {
+			_, ok := Lookup(name)
+			assert.True(t, ok, "alert %s references %s, which is not in the catalog", rule.name, name)
+		}
+	}
+}
+
+// TestGeneratedAssetsAreCurrent keeps the committed docs in step with the
+// catalog; run `make metrics-catalog` after changing it
+func TestGeneratedAssetsAreCurrent(t *testing.T) {
+	root := filepath.Join("..", "..")
+
+	var catalog bytes.Buffer
+	require.NoError(t, WriteCatalog(&catalog, CatalogFormatMarkdown, "groningen"))
+	dashboard, err := GrafanaDashboard("groningen")
+	require.NoError(t, err)
+
+	for path, want := range map[string][]byte{
+		"docs/metrics-catalog.md":                catalog.Bytes(),
+		"docs/monitoring/grafana-dashboard.json": dashboard,
+		"docs/monitoring/prometheus-alerts.yaml": AlertRules("groningen"),
+	} {
+		got, err := os.ReadFile(filepath.Join(root, path))
+		require.NoError(t, err)
+		assert.Equal(t, string(want), string(got), "%s is stale; run make metrics-catalog", path)
+	}
+}
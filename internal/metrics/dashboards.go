@@ -0,0 +1,342 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fulmenhq/gofulmen/telemetry"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+// Grafana dashboard model; only the fields the generated dashboard sets
+type (
+	grafanaDashboard struct {
+		UID           string            `json:"uid"`
+		Title         string            `json:"title"`
+		Description   string            `json:"description"`
+		Tags          []string          `json:"tags"`
+		SchemaVersion int               `json:"schemaVersion"`
+		Editable      bool              `json:"editable"`
+		Refresh       string            `json:"refresh"`
+		Time          grafanaTimeRange  `json:"time"`
+		Templating    grafanaTemplating `json:"templating"`
+		Panels        []grafanaPanel    `json:"panels"`
+	}
+	grafanaTimeRange struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}
+	grafanaTemplating struct {
+		List []grafanaVariable `json:"list"`
+	}
+	grafanaVariable struct {
+		Name  string `json:"name"`
+		Label string `json:"label"`
+		Type  string `json:"type"`
+		Query string `json:"query"`
+	}
+	grafanaPanel struct {
+		ID          int                 `json:"id"`
+		Type        string              `json:"type"`
+		Title       string              `json:"title"`
+		Description string              `json:"description,omitempty"`
+		GridPos     grafanaGridPos      `json:"gridPos"`
+		Collapsed   *bool               `json:"collapsed,omitempty"`
+		Datasource  *grafanaDatasource  `json:"datasource,omitempty"`
+		FieldConfig *grafanaFieldConfig `json:"fieldConfig,omitempty"`
+		Targets     []grafanaTarget     `json:"targets,omitempty"`
+	}
+	grafanaGridPos struct {
+		H int `json:"h"`
+		W int `json:"w"`
+		X int `json:"x"`
+		Y int `json:"y"`
+	}
+	grafanaDatasource struct {
+		Type string `json:"type"`
+		UID  string `json:"uid"`
+	}
+	grafanaFieldConfig struct {
+		Defaults  grafanaFieldDefaults `json:"defaults"`
+		Overrides []any                `json:"overrides"`
+	}
+	grafanaFieldDefaults struct {
+		Unit string `json:"unit,omitempty"`
+	}
+	grafanaTarget struct {
+		RefID        string `json:"refId"`
+		Expr         string `json:"expr"`
+		LegendFormat string `json:"legendFormat,omitempty"`
+	}
+)
+
+const (
+	panelWidth  = 12
+	panelHeight = 8
+)
+
+// GrafanaDashboard generates a starter dashboard with one row per catalog
+// group and one panel per metric: counters as per-second rates, histograms as
+// p95, gauges as-is. namespace must match the scraped metric prefix.
+func GrafanaDashboard(namespace string) ([]byte, error) {
+	datasource := &grafanaDatasource{Type: "prometheus", UID: "${datasource}"}
+	dashboard := grafanaDashboard{
+		UID:           namespace + "-overview",
+		Title:         namespace + " overview",
+		Description:   fmt.Sprintf("Generated by `%s metrics dashboard` from the metrics catalog", namespace),
+		Tags:          []string{namespace, "generated"},
+		SchemaVersion: 39,
+		Editable:      true,
+		Refresh:       "30s",
+		Time:          grafanaTimeRange{From: "now-6h", To: "now"},
+		Templating: grafanaTemplating{List: []grafanaVariable{
+			{Name: "datasource", Label: "Data source", Type: "datasource", Query: "prometheus"},
+		}},
+	}
+
+	id, y, column := 0, 0, 0
+	group := ""
+	for _, def := range Catalog() {
+		if def.Group != group {
+			group = def.Group
+			if column > 0 {
+				y += panelHeight
+				column = 0
+			}
+			id++
+			collapsed := false
+			dashboard.Panels = append(dashboard.Panels, grafanaPanel{
+				ID:        id,
+				Type:      "row",
+				Title:     groupTitle(group),
+				GridPos:   grafanaGridPos{H: 1, W: 24, Y: y},
+				Collapsed: &collapsed,
+			})
+			y++
+		}
+
+		id++
+		expr, legend, title := panelQuery(namespace, def)
+		dashboard.Panels = append(dashboard.Panels, grafanaPanel{
+			ID:          id,
+			Type:        "timeseries",
+			Title:       title,
+			Description: def.Help,
+			GridPos:     grafanaGridPos{H: panelHeight, W: panelWidth, X: column * panelWidth, Y: y},
+			Datasource:  datasource,
+			FieldConfig: &grafanaFieldConfig{
+				Defaults:  grafanaFieldDefaults{Unit: panelUnit(def)},
+				Overrides: []any{},
+			},
+			Targets: []grafanaTarget{{RefID: "A", Expr: expr, LegendFormat: legend}},
+		})
+
+		column++
+		if column*panelWidth >= 24 {
+			y += panelHeight
+			column = 0
+		}
+	}
+
+	out, err := json.MarshalIndent(dashboard, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// panelQuery returns the PromQL, legend and title for a metric's panel
+func panelQuery(namespace string, def Definition) (expr, legend, title string) {
+	name := observability.ExposedName(namespace, def.Name)
+	by := strings.Join(def.Labels, ", ")
+	legend = legendFormat(def.Labels)
+
+	switch def.Type {
+	case telemetry.TypeCounter:
+		rate := fmt.Sprintf("rate(%s[$__rate_interval])", name)
+		if by == "" {
+			return fmt.Sprintf("sum(%s)", rate), def.Name, def.Name
+		}
+		return fmt.Sprintf("sum by (%s) (%s)", by, rate), legend, def.Name
+
+	case telemetry.TypeHistogram:
+		grouping := "le"
+		if by != "" {
+			grouping = "le, " + by
+		}
+		return fmt.Sprintf("histogram_quantile(0.95, sum by (%s) (rate(%s_bucket[$__rate_interval])))", grouping, name),
+			legend, def.Name + " p95"
+
+	default:
+		if legend == "" {
+			legend = "{{instance}}"
+		}
+		return name, legend, def.Name
+	}
+}
+
+func legendFormat(labels []string) string {
+	parts := make([]string, len(labels))
+	for i, label := range labels {
+		parts[i] = "{{" + label + "}}"
+	}
+	return strings.Join(parts, " ")
+}
+
+// panelUnit maps a catalog unit to a Grafana unit; counters are shown as rates
+func panelUnit(def Definition) string {
+	if def.Type == telemetry.TypeCounter {
+		switch def.Unit {
+		case "bytes":
+			return "Bps"
+		case "seconds":
+			return "short"
+		default:
+			return "ops"
+		}
+	}
+	switch def.Unit {
+	case "seconds":
+		return "s"
+	case "milliseconds":
+		return "ms"
+	case "bytes":
+		return "bytes"
+	case "ratio":
+		return "percentunit"
+	case "timestamp":
+		return "dateTimeFromNow"
+	default:
+		return "short"
+	}
+}
+
+// alertRule is one Prometheus alerting rule; expr receives the exposed name
+// of each metric it references, in order
+type alertRule struct {
+	name     string
+	metrics  []string
+	expr     string
+	duration string
+	severity string
+	summary  string
+}
+
+// alertRules are the starter alerts. They reference metrics through the
+// catalog names so a rename cannot leave a rule querying a dead series.
+var alertRules = []alertRule{
+	{
+		name:     "HighErrorRate",
+		metrics:  []string{HTTPRequestsTotal, HTTPRequestsTotal},
+		expr:     `sum(rate(%s{status=~"5.."}[5m])) / sum(rate(%s[5m])) > 0.05`,
+		duration: "5m",
+		severity: "critical",
+		summary:  "More than 5% of HTTP requests are failing with 5xx",
+	},
+	{
+		name:     "HighLatency",
+		metrics:  []string{HTTPRequestDuration},
+		expr:     `histogram_quantile(0.95, sum by (le) (rate(%s_bucket[5m]))) > 500`,
+		duration: "10m",
+		severity: "warning",
+		summary:  "p95 HTTP latency is above 500ms",
+	},
+	{
+		name:     "DependencyNotReady",
+		metrics:  []string{HealthStatus},
+		expr:     `min by (check) (%s{probe="ready"}) == 0`,
+		duration: "5m",
+		severity: "critical",
+		summary:  "Readiness check {{ $labels.check }} has been failing for 5 minutes",
+	},
+	{
+		name:     "PanicsRecovered",
+		metrics:  []string{PanicsTotalName},
+		expr:     `increase(%s[5m]) > 0`,
+		duration: "0m",
+		severity: "warning",
+		summary:  "The server recovered from a panic",
+	},
+	{
+		name:     "FileDescriptorsNearLimit",
+		metrics:  []string{ProcessOpenFDs, ProcessMaxFDs},
+		expr:     `%s / %s > 0.9`,
+		duration: "10m",
+		severity: "warning",
+		summary:  "Open file descriptors are above 90% of the limit",
+	},
+	{
+		name:     "SLOBudgetBurnPage",
+		metrics:  []string{SLOAlertFiring},
+		expr:     `%s{severity="page"} == 1`,
+		duration: "0m",
+		severity: "critical",
+		summary:  "SLO {{ $labels.slo }} is burning its error budget fast",
+	},
+	{
+		name:     "SLOBudgetBurnTicket",
+		metrics:  []string{SLOAlertFiring},
+		expr:     `%s{severity="ticket"} == 1`,
+		duration: "0m",
+		severity: "warning",
+		summary:  "SLO {{ $labels.slo }} is burning its error budget steadily",
+	},
+	{
+		name:     "MetricsCardinalityOverflow",
+		metrics:  []string{observability.CardinalityOverflowMetric},
+		expr:     `sum by (metric, label) (increase(%s[15m])) > 0`,
+		duration: "0m",
+		severity: "info",
+		summary:  "Label {{ $labels.label }} on {{ $labels.metric }} is hitting the cardinality cap",
+	},
+	{
+		name:     "MetricsPushFailing",
+		metrics:  []string{observability.PushErrorsMetric},
+		expr:     `sum by (exporter) (increase(%s[15m])) > 0`,
+		duration: "15m",
+		severity: "warning",
+		summary:  "Pushing metrics to {{ $labels.exporter }} keeps failing",
+	},
+}
+
+// AlertRules generates a Prometheus alerting rules file. Alert names are
+// prefixed with the title-cased namespace.
+func AlertRules(namespace string) []byte {
+	prefix := alertPrefix(namespace)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Generated by `%s metrics alerts` from the metrics catalog.\n", namespace)
+	b.WriteString("# Thresholds are starting points; tune them before paging anyone.\n")
+	b.WriteString("groups:\n")
+	fmt.Fprintf(&b, "  - name: %s\n", namespace)
+	b.WriteString("    rules:\n")
+	for _, rule := range alertRules {
+		names := make([]any, len(rule.metrics))
+		for i, metric := range rule.metrics {
+			names[i] = observability.ExposedName(namespace, metric)
+		}
+
+		fmt.Fprintf(&b, "      - alert: %s%s\n", prefix, rule.name)
+		fmt.Fprintf(&b, "        expr: %s\n", strconv.Quote(fmt.Sprintf(rule.expr, names...)))
+		fmt.Fprintf(&b, "        for: %s\n", rule.duration)
+		b.WriteString("        labels:\n")
+		fmt.Fprintf(&b, "          severity: %s\n", rule.severity)
+		b.WriteString("        annotations:\n")
+		fmt.Fprintf(&b, "          summary: %s\n", strconv.Quote(rule.summary))
+	}
+	return []byte(b.String())
+}
+
+// alertPrefix turns a namespace such as "my_service" into "MyService"
+func alertPrefix(namespace string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(namespace, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.'
+	}) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
@@ -17,7 +17,7 @@ const (
 func RecordError(errorCode string, httpStatus int) {
 	if observability.TelemetrySystem != nil {
 		_ = observability.TelemetrySystem.Counter(
-			"errors_total",
+			ErrorsTotalName,
 			1,
 			map[string]string{
 				"error_code":  errorCode,
@@ -31,7 +31,7 @@ func RecordError(errorCode string, httpStatus int) {
 func RecordPanic() {
 	if observability.TelemetrySystem != nil {
 		_ = observability.TelemetrySystem.Counter(
-			"panics_total",
+			PanicsTotalName,
 			1,
 			nil,
 		)
@@ -42,7 +42,7 @@ func RecordPanic() {
 func RecordErrorByEndpoint(endpoint string, errorCode string) {
 	if observability.TelemetrySystem != nil {
 		_ = observability.TelemetrySystem.Counter(
-			"errors_by_endpoint",
+			ErrorsByEndpointName,
 			1,
 			map[string]string{
 				"endpoint":   endpoint,
@@ -0,0 +1,249 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fulmenhq/gofulmen/telemetry"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+// Instruments are created once (typically as package variables) with a fixed
+// list of label names, then bound to concrete label values with With. Bound
+// instruments are cached, so the hot path reuses the same label map instead
+// of allocating one per call.
+//
+//	var requests = metrics.NewCounterVec("http_requests_total", "method", "status")
+//	requests.With("GET", "200").Inc()
+
+// maxInstrumentLabels bounds the label count so label sets can be map keys without allocating
+const maxInstrumentLabels = 4
+
+// maxBoundInstruments caps the bound-instrument cache of each family. Label
+// sets beyond it are still recorded (and collapsed by the cardinality guard)
+// but bound per call, so unbounded label values cannot grow the cache.
+const maxBoundInstruments = 1024
+
+type labelKey [maxInstrumentLabels]string
+
+// vec caches bound instruments by label values
+type vec[T any] struct {
+	name   string
+	labels []string
+	bind   func(tags map[string]string) *T
+
+	mu    sync.RWMutex
+	bound map[labelKey]*T
+}
+
+func newVec[T any](name string, labels []string, bind func(map[string]string) *T) vec[T] {
+	if len(labels) > maxInstrumentLabels {
+		panic(fmt.Sprintf("metrics: %s declares %d labels; at most %d are supported", name, len(labels), maxInstrumentLabels))
+	}
+	return vec[T]{
+		name:   name,
+		labels: labels,
+		bind:   bind,
+		bound:  make(map[labelKey]*T),
+	}
+}
+
+func (v *vec[T]) with(values []string) *T {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", v.name, len(v.labels), len(values)))
+	}
+
+	var key labelKey
+	copy(key[:], values)
+
+	v.mu.RLock()
+	instrument, ok := v.bound[key]
+	v.mu.RUnlock()
+	if ok {
+		return instrument
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if instrument, ok := v.bound[key]; ok {
+		return instrument
+	}
+
+	var tags map[string]string
+	if len(v.labels) > 0 {
+		tags = make(map[string]string, len(v.labels))
+		for i, label := range v.labels {
+			tags[label] = values[i]
+		}
+	}
+	instrument = v.bind(tags)
+	if len(v.bound) < maxBoundInstruments {
+		v.bound[key] = instrument
+	}
+	return instrument
+}
+
+// CounterVec is a counter family with fixed label names
+type CounterVec struct {
+	vec[Counter]
+}
+
+// Counter is a counter bound to one label set
+type Counter struct {
+	name string
+	tags map[string]string
+}
+
+// NewCounterVec creates a counter family
+func NewCounterVec(name string, labelNames ...string) *CounterVec {
+	return &CounterVec{newVec(name, labelNames, func(tags map[string]string) *Counter {
+		return &Counter{name: name, tags: tags}
+	})}
+}
+
+// With returns the counter bound to the given label values (in declaration order)
+func (v *CounterVec) With(labelValues ...string) *Counter {
+	return v.with(labelValues)
+}
+
+// Add increments the counter by delta
+func (c *Counter) Add(delta float64) {
+	if sys := observability.TelemetrySystem; sys != nil {
+		_ = sys.Counter(c.name, delta, c.tags)
+	}
+}
+
+// Inc increments the counter by one
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// GaugeVec is a gauge family with fixed label names
+type GaugeVec struct {
+	vec[Gauge]
+}
+
+// Gauge is a gauge bound to one label set
+type Gauge struct {
+	name string
+	tags map[string]string
+}
+
+// NewGaugeVec creates a gauge family
+func NewGaugeVec(name string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{newVec(name, labelNames, func(tags map[string]string) *Gauge {
+		return &Gauge{name: name, tags: tags}
+	})}
+}
+
+// With returns the gauge bound to the given label values (in declaration order)
+func (v *GaugeVec) With(labelValues ...string) *Gauge {
+	return v.with(labelValues)
+}
+
+// Set records the current value
+func (g *Gauge) Set(value float64) {
+	if sys := observability.TelemetrySystem; sys != nil {
+		_ = sys.Gauge(g.name, value, g.tags)
+	}
+}
+
+// HistogramVec is a histogram family with fixed label names. Bucket bounds
+// come from metrics.buckets (observability.HistogramBuckets) and are resolved
+// when a label set is first bound.
+type HistogramVec struct {
+	vec[Histogram]
+}
+
+// Histogram is a histogram bound to one label set
+type Histogram struct {
+	name   string
+	tags   map[string]string
+	series string
+
+	bounds []float64
+	// cumulative holds the precomputed bucket vector for an observation
+	// landing in each bucket (index len(bounds) is the +Inf bucket)
+	cumulative [][]telemetry.HistogramBucket
+}
+
+// NewHistogramVec creates a histogram family with default bucket bounds
+func NewHistogramVec(name string, defaultBuckets []float64, labelNames ...string) *HistogramVec {
+	return &HistogramVec{newVec(name, labelNames, func(tags map[string]string) *Histogram {
+		return newHistogram(name, observability.HistogramBuckets(name, defaultBuckets), tags)
+	})}
+}
+
+func newHistogram(name string, bounds []float64, tags map[string]string) *Histogram {
+	h := &Histogram{
+		name:       name,
+		tags:       tags,
+		series:     observability.SeriesKey(tags),
+		bounds:     bounds,
+		cumulative: make([][]telemetry.HistogramBucket, len(bounds)+1),
+	}
+	for first := range h.cumulative {
+		buckets := make([]telemetry.HistogramBucket, 0, len(bounds)+1)
+		for i, le := range bounds {
+			var count int64
+			if i >= first {
+				count = 1
+			}
+			buckets = append(buckets, telemetry.HistogramBucket{LE: le, Count: count})
+		}
+		buckets = append(buckets, telemetry.HistogramBucket{LE: math.Inf(1), Count: 1})
+		h.cumulative[first] = buckets
+	}
+	return h
+}
+
+// With returns the histogram bound to the given label values (in declaration order)
+func (v *HistogramVec) With(labelValues ...string) *Histogram {
+	return v.with(labelValues)
+}
+
+// Observe records a single value (milliseconds for `_ms` metrics)
+func (h *Histogram) Observe(value float64) {
+	h.observe(value, sort.SearchFloat64s(h.bounds, value))
+}
+
+// ObserveWithExemplar records a value and attaches exemplarLabels (such as
+// request_id) to the bucket it lands in. Exemplars appear in OpenMetrics output.
+func (h *Histogram) ObserveWithExemplar(value float64, exemplarLabels map[string]string) {
+	bucket := sort.SearchFloat64s(h.bounds, value)
+	if !h.observe(value, bucket) || len(exemplarLabels) == 0 {
+		return
+	}
+
+	le := math.Inf(1)
+	if bucket < len(h.bounds) {
+		le = h.bounds[bucket]
+	}
+	observability.RecordExemplar(h.name, h.series, le, observability.Exemplar{
+		Labels:    exemplarLabels,
+		Value:     value,
+		Timestamp: time.Now(),
+	})
+}
+
+func (h *Histogram) observe(value float64, bucket int) bool {
+	sys := observability.TelemetrySystem
+	if sys == nil {
+		return false
+	}
+	_ = sys.HistogramSummary(h.name, telemetry.HistogramSummary{
+		Count:   1,
+		Sum:     value,
+		Buckets: h.cumulative[bucket],
+	}, h.tags)
+	return true
+}
+
+// ObserveDuration records a duration in milliseconds
+func (h *Histogram) ObserveDuration(d time.Duration) {
+	h.Observe(float64(d.Nanoseconds()) / 1e6)
+}
@@ -0,0 +1,155 @@
+package metrics
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/fulmenhq/gofulmen/telemetry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+func TestCounterVecBindsLabelSetsOnce(t *testing.T) {
+	collector := setupTelemetry(t)
+
+	requests := NewCounterVec("test_requests_total", "method", "status")
+	a := requests.With("GET", "200")
+	assert.Same(t, a, requests.With("GET", "200"), "same label values should return the cached instrument")
+	assert.NotSame(t, a, requests.With("GET", "500"))
+
+	a.Inc()
+	a.Add(2)
+
+	events := collector.GetMetricsByName("test_requests_total")
+	require.Len(t, events, 2)
+	assert.Equal(t, map[string]string{"method": "GET", "status": "200"}, events[0].Tags)
+	value, _ := events[1].Value.(float64)
+	assert.Equal(t, 2.0, value)
+
+	assert.Panics(t, func() { requests.With("GET") }, "wrong label count is a programming error")
+	assert.Panics(t, func() { NewCounterVec("too_many", "a", "b", "c", "d", "e") })
+}
+
+func TestVecCapsBoundInstruments(t *testing.T) {
+	collector := setupTelemetry(t)
+
+	requests := NewCounterVec("test_capped_total", "id")
+	for i := 0; i < maxBoundInstruments+10; i++ {
+		requests.With(strconv.Itoa(i)).Inc()
+	}
+
+	assert.Len(t, requests.bound, maxBoundInstruments, "the cache stops growing at its cap")
+	assert.Equal(t, maxBoundInstruments+10, collector.CountMetricsByName("test_capped_total"),
+		"label sets beyond the cap are still recorded")
+}
+
+func TestHistogramVecUsesConfiguredBuckets(t *testing.T) {
+	collector := setupTelemetry(t)
+	require.NoError(t, observability.SetHistogramBuckets(map[string][]float64{"test_latency_ms": {10, 100}}))
+	t.Cleanup(func() { _ = observability.SetHistogramBuckets(nil) })
+
+	latency := NewHistogramVec("test_latency_ms", []float64{1}, "route").With("/a")
+	latency.ObserveDuration(50 * time.Millisecond)
+	latency.Observe(500)
+
+	events := collector.GetMetricsByName("test_latency_ms")
+	require.Len(t, events, 2)
+
+	first, ok := events[0].Value.(telemetry.HistogramSummary)
+	require.True(t, ok)
+	assert.Equal(t, 50.0, first.Sum)
+	assert.Equal(t, []int64{0, 1, 1}, bucketCounts(first))
+
+	second, _ := events[1].Value.(telemetry.HistogramSummary)
+	assert.Equal(t, []int64{0, 0, 1}, bucketCounts(second), "values above the last bound land in +Inf only")
+}
+
+func TestGaugeVecSet(t *testing.T) {
+	collector := setupTelemetry(t)
+
+	NewGaugeVec("test_queue_depth").With().Set(7)
+
+	events := collector.GetMetricsByName("test_queue_depth")
+	require.Len(t, events, 1)
+	assert.Nil(t, events[0].Tags)
+}
+
+func bucketCounts(summary telemetry.HistogramSummary) []int64 {
+	counts := make([]int64, len(summary.Buckets))
+	for i, b := range summary.Buckets {
+		counts[i] = b.Count
+	}
+	return counts
+}
+
+// discardEmitter drops every metric so benchmarks measure only instrumentation overhead
+type discardEmitter struct{}
+
+func (discardEmitter) Counter(string, float64, map[string]string) error         { return nil }
+func (discardEmitter) Histogram(string, time.Duration, map[string]string) error { return nil }
+func (discardEmitter) HistogramSummary(string, telemetry.HistogramSummary, map[string]string) error {
+	return nil
+}
+func (discardEmitter) Gauge(string, float64, map[string]string) error { return nil }
+
+func setupDiscardTelemetry(b *testing.B) {
+	b.Helper()
+	sys, err := telemetry.NewSystem(&telemetry.Config{Enabled: true, Emitter: discardEmitter{}})
+	require.NoError(b, err)
+
+	original := observability.TelemetrySystem
+	observability.TelemetrySystem = sys
+	b.Cleanup(func() { observability.TelemetrySystem = original })
+}
+
+// BenchmarkRequestInstrumentationByName mirrors the previous per-request
+// pattern: fresh label maps and string-keyed calls for every request.
+func BenchmarkRequestInstrumentationByName(b *testing.B) {
+	setupDiscardTelemetry(b)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		sys := observability.TelemetrySystem
+		common := map[string]string{"method": "GET", "endpoint": "/items/{id}", "status": strconv.Itoa(200)}
+		_ = sys.Counter("http_requests_total", 1, common)
+		observability.ObserveHistogram("http_request_duration_ms", 12, telemetry.DefaultHistogramBucketsMS, common)
+		sizes := map[string]string{"method": "GET", "endpoint": "/items/{id}"}
+		observability.ObserveHistogram("http_request_size_bytes", 0, observability.DefaultSizeBucketsBytes, sizes)
+		observability.ObserveHistogram("http_response_size_bytes", 512, observability.DefaultSizeBucketsBytes, sizes)
+	}
+}
+
+// BenchmarkRequestInstrumentationBound records the same series through bound instruments.
+func BenchmarkRequestInstrumentationBound(b *testing.B) {
+	setupDiscardTelemetry(b)
+	benchmarkBoundInstruments(b)
+}
+
+// BenchmarkRequestInstrumentationRegistry measures the full recording path:
+// bound instruments, the cardinality guard and the in-process registry.
+func BenchmarkRequestInstrumentationRegistry(b *testing.B) {
+	require.NoError(b, observability.InitMetrics("bench", 0, "bench"))
+	b.Cleanup(func() {
+		observability.MetricsRegistry = nil
+		observability.TelemetrySystem = nil
+	})
+	benchmarkBoundInstruments(b)
+}
+
+func benchmarkBoundInstruments(b *testing.B) {
+	requests := NewCounterVec("http_requests_total", "method", "endpoint", "status")
+	duration := NewHistogramVec("http_request_duration_ms", telemetry.DefaultHistogramBucketsMS, "method", "endpoint", "status")
+	reqSize := NewHistogramVec("http_request_size_bytes", observability.DefaultSizeBucketsBytes, "method", "endpoint")
+	respSize := NewHistogramVec("http_response_size_bytes", observability.DefaultSizeBucketsBytes, "method", "endpoint")
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		requests.With("GET", "/items/{id}", "200").Inc()
+		duration.With("GET", "/items/{id}", "200").Observe(12)
+		reqSize.With("GET", "/items/{id}").Observe(0)
+		respSize.With("GET", "/items/{id}").Observe(512)
+	}
+}
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+// Process metrics (Linux only; other platforms emit nothing)
+var (
+	ProcessCPUSecondsTotal     = "process_cpu_seconds_total"
+	ProcessResidentMemoryBytes = "process_resident_memory_bytes"
+	ProcessOpenFDs             = "process_open_fds"
+	ProcessMaxFDs              = "process_max_fds"
+	ProcessThreads             = "process_threads"
+)
+
+// processStats is a point-in-time snapshot of the current process
+type processStats struct {
+	cpuSeconds    float64
+	residentBytes float64
+	openFDs       float64
+	maxFDs        float64
+	threads       float64
+}
+
+// ProcessCollector exports process CPU, memory, file descriptor and thread counts
+type ProcessCollector struct {
+	mu         sync.Mutex
+	cpuSeconds float64
+	seen       bool
+}
+
+// NewProcessCollector creates a process stats collector
+func NewProcessCollector() *ProcessCollector {
+	return &ProcessCollector{}
+}
+
+// Collect reads process stats and emits them through the telemetry system
+func (c *ProcessCollector) Collect() {
+	sys := observability.TelemetrySystem
+	if sys == nil {
+		return
+	}
+
+	stats, ok := readProcessStats()
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	delta := stats.cpuSeconds - c.cpuSeconds
+	first := !c.seen
+	c.cpuSeconds, c.seen = stats.cpuSeconds, true
+	c.mu.Unlock()
+
+	if delta > 0 || first {
+		_ = sys.Counter(ProcessCPUSecondsTotal, delta, nil)
+	}
+	_ = sys.Gauge(ProcessResidentMemoryBytes, stats.residentBytes, nil)
+	_ = sys.Gauge(ProcessOpenFDs, stats.openFDs, nil)
+	_ = sys.Gauge(ProcessMaxFDs, stats.maxFDs, nil)
+	_ = sys.Gauge(ProcessThreads, stats.threads, nil)
+}
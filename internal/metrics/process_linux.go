@@ -0,0 +1,54 @@
+//go:build linux
+
+package metrics
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// clockTicks is USER_HZ, which is 100 on every mainstream Linux platform
+const clockTicks = 100
+
+// readProcessStats reads /proc/self/stat, /proc/self/fd and RLIMIT_NOFILE
+func readProcessStats() (processStats, bool) {
+	data, err := os.ReadFile("/proc/self/stat")
+	if err != nil {
+		return processStats{}, false
+	}
+
+	// The command name is parenthesised and may contain spaces; fields
+	// after it start at field 3 (state)
+	stat := string(data)
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return processStats{}, false
+	}
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 22 {
+		return processStats{}, false
+	}
+	field := func(n int) float64 {
+		v, _ := strconv.ParseFloat(fields[n-3], 64)
+		return v
+	}
+
+	stats := processStats{
+		cpuSeconds:    (field(14) + field(15)) / clockTicks,
+		threads:       field(20),
+		residentBytes: field(24) * float64(os.Getpagesize()),
+	}
+
+	if entries, err := os.ReadDir("/proc/self/fd"); err == nil {
+		stats.openFDs = float64(len(entries))
+	}
+
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err == nil {
+		stats.maxFDs = float64(limit.Cur)
+	}
+
+	return stats, true
+}
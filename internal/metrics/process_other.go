@@ -0,0 +1,8 @@
+//go:build !linux
+
+package metrics
+
+// readProcessStats is only implemented on Linux
+func readProcessStats() (processStats, bool) {
+	return processStats{}, false
+}
@@ -0,0 +1,216 @@
+package metrics
+
+import (
+	"math"
+	"runtime"
+	rtmetrics "runtime/metrics"
+	"sync"
+
+	"github.com/fulmenhq/gofulmen/crucible"
+	"github.com/fulmenhq/gofulmen/telemetry"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+// Build and runtime metrics
+var (
+	BuildInfo = "app_build_info"
+
+	GoGoroutines          = "go_goroutines"
+	GoMaxProcs            = "go_gomaxprocs"
+	GoHeapObjectsBytes    = "go_heap_objects_bytes"
+	GoHeapObjects         = "go_heap_objects"
+	GoHeapGoalBytes       = "go_gc_heap_goal_bytes"
+	GoMemoryTotalBytes    = "go_memory_total_bytes"
+	GoGCCyclesTotal       = "go_gc_cycles_total"
+	GoHeapAllocsTotal     = "go_gc_heap_allocs_bytes_total"
+	GoGCPauseSeconds      = "go_gc_pause_seconds"
+	GoSchedLatencySeconds = "go_sched_latency_seconds"
+)
+
+// runtimeBucketsMS are the histogram boundaries used for GC pause and
+// scheduler latency (10µs to 100ms); runtime/metrics buckets are folded into these
+var runtimeBucketsMS = []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 50, 100}
+
+// runtimeGauges maps runtime/metrics samples to gauge names
+var runtimeGauges = map[string]string{
+	"/sched/goroutines:goroutines":       GoGoroutines,
+	"/sched/gomaxprocs:threads":          GoMaxProcs,
+	"/memory/classes/heap/objects:bytes": GoHeapObjectsBytes,
+	"/gc/heap/objects:objects":           GoHeapObjects,
+	"/gc/heap/goal:bytes":                GoHeapGoalBytes,
+	"/memory/classes/total:bytes":        GoMemoryTotalBytes,
+}
+
+// runtimeCounters maps cumulative runtime/metrics samples to counter names
+var runtimeCounters = map[string]string{
+	"/gc/cycles/total:gc-cycles": GoGCCyclesTotal,
+	"/gc/heap/allocs:bytes":      GoHeapAllocsTotal,
+}
+
+// runtimeHistograms maps runtime/metrics histograms (in seconds) to histogram names
+var runtimeHistograms = map[string]string{
+	"/sched/pauses/total/gc:seconds": GoGCPauseSeconds,
+	"/sched/latencies:seconds":       GoSchedLatencySeconds,
+}
+
+// histogramState is the cumulative state last emitted for a runtime histogram
+type histogramState struct {
+	count   int64
+	sum     float64
+	buckets []int64
+}
+
+// RuntimeCollector exports Go runtime/metrics data. Cumulative runtime values
+// are emitted as deltas between collections so they aggregate correctly.
+type RuntimeCollector struct {
+	mu         sync.Mutex
+	samples    []rtmetrics.Sample
+	counters   map[string]float64
+	histograms map[string]histogramState
+}
+
+// NewRuntimeCollector creates a collector for the runtime metrics supported
+// by the running Go version
+func NewRuntimeCollector() *RuntimeCollector {
+	supported := make(map[string]bool)
+	for _, desc := range rtmetrics.All() {
+		supported[desc.Name] = true
+	}
+
+	c := &RuntimeCollector{
+		counters:   make(map[string]float64),
+		histograms: make(map[string]histogramState),
+	}
+	for _, set := range []map[string]string{runtimeGauges, runtimeCounters, runtimeHistograms} {
+		for name := range set {
+			if supported[name] {
+				c.samples = append(c.samples, rtmetrics.Sample{Name: name})
+			}
+		}
+	}
+	return c
+}
+
+// Collect reads runtime metrics and emits them through the telemetry system
+func (c *RuntimeCollector) Collect() {
+	sys := observability.TelemetrySystem
+	if sys == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rtmetrics.Read(c.samples)
+	for _, sample := range c.samples {
+		if name, ok := runtimeGauges[sample.Name]; ok {
+			_ = sys.Gauge(name, sampleValue(sample.Value), nil)
+			continue
+		}
+
+		if name, ok := runtimeCounters[sample.Name]; ok {
+			value := sampleValue(sample.Value)
+			previous, seen := c.counters[sample.Name]
+			c.counters[sample.Name] = value
+			if delta := value - previous; delta > 0 || !seen {
+				_ = sys.Counter(name, delta, nil)
+			}
+			continue
+		}
+
+		if name, ok := runtimeHistograms[sample.Name]; ok && sample.Value.Kind() == rtmetrics.KindFloat64Histogram {
+			current := foldHistogram(sample.Value.Float64Histogram())
+			previous, seen := c.histograms[sample.Name]
+			c.histograms[sample.Name] = current
+			if current.count > previous.count || !seen {
+				_ = sys.HistogramSummary(name, histogramDelta(current, previous), nil)
+			}
+		}
+	}
+}
+
+// foldHistogram maps a runtime histogram (seconds) onto runtimeBucketsMS.
+// runtime/metrics does not track a sum, so it is approximated from bucket midpoints.
+func foldHistogram(h *rtmetrics.Float64Histogram) histogramState {
+	state := histogramState{buckets: make([]int64, len(runtimeBucketsMS))}
+	for i, count := range h.Counts {
+		if count == 0 {
+			continue
+		}
+		lower, upper := h.Buckets[i]*1000, h.Buckets[i+1]*1000
+
+		state.count += int64(count)
+		switch {
+		case math.IsInf(lower, -1):
+			state.sum += upper * float64(count)
+		case math.IsInf(upper, 1):
+			state.sum += lower * float64(count)
+		default:
+			state.sum += (lower + upper) / 2 * float64(count)
+		}
+
+		for j, le := range runtimeBucketsMS {
+			if upper <= le {
+				state.buckets[j] += int64(count)
+			}
+		}
+	}
+	return state
+}
+
+func histogramDelta(current, previous histogramState) telemetry.HistogramSummary {
+	summary := telemetry.HistogramSummary{
+		Count:   current.count - previous.count,
+		Sum:     current.sum - previous.sum,
+		Buckets: make([]telemetry.HistogramBucket, 0, len(runtimeBucketsMS)+1),
+	}
+	for i, le := range runtimeBucketsMS {
+		count := current.buckets[i]
+		if previous.buckets != nil {
+			count -= previous.buckets[i]
+		}
+		summary.Buckets = append(summary.Buckets, telemetry.HistogramBucket{LE: le, Count: count})
+	}
+	summary.Buckets = append(summary.Buckets, telemetry.HistogramBucket{LE: math.Inf(1), Count: summary.Count})
+	return summary
+}
+
+func sampleValue(v rtmetrics.Value) float64 {
+	switch v.Kind() {
+	case rtmetrics.KindUint64:
+		return float64(v.Uint64())
+	case rtmetrics.KindFloat64:
+		return v.Float64()
+	default:
+		return 0
+	}
+}
+
+// SetBuildInfo publishes app_build_info, a constant gauge whose labels carry
+// the build version, commit, Go version and gofulmen/Crucible versions
+func SetBuildInfo(version, commit string) {
+	if observability.TelemetrySystem == nil {
+		return
+	}
+
+	deps := crucible.GetVersion()
+	_ = observability.TelemetrySystem.Gauge(
+		BuildInfo,
+		1,
+		map[string]string{
+			"version":    version,
+			"commit":     commit,
+			"go_version": runtime.Version(),
+			"gofulmen":   deps.Gofulmen,
+			"crucible":   deps.Crucible,
+		},
+	)
+}
+
+// RegisterRuntimeCollectors registers the Go runtime and process collectors
+// so they refresh on every scrape
+func RegisterRuntimeCollectors() {
+	observability.RegisterCollector(NewRuntimeCollector().Collect)
+	observability.RegisterCollector(NewProcessCollector().Collect)
+}
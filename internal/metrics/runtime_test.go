@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/fulmenhq/gofulmen/telemetry"
+	telemetrytesting "github.com/fulmenhq/gofulmen/telemetry/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+func setupTelemetry(t *testing.T) *telemetrytesting.FakeCollector {
+	t.Helper()
+
+	collector := telemetrytesting.NewFakeCollector()
+	sys, err := telemetry.NewSystem(&telemetry.Config{Enabled: true, Emitter: collector})
+	require.NoError(t, err)
+
+	original := observability.TelemetrySystem
+	observability.TelemetrySystem = sys
+	t.Cleanup(func() {
+		observability.TelemetrySystem = original
+	})
+
+	return collector
+}
+
+func TestRuntimeCollectorEmitsGaugesAndDeltas(t *testing.T) {
+	collector := setupTelemetry(t)
+
+	runtime.GC()
+	c := NewRuntimeCollector()
+	c.Collect()
+
+	assert.Greater(t, collector.CountMetricsByName(GoGoroutines), 0)
+	assert.Greater(t, collector.CountMetricsByName(GoHeapObjectsBytes), 0)
+	assert.Equal(t, 1, collector.CountMetricsByName(GoGCCyclesTotal))
+	assert.Equal(t, 1, collector.CountMetricsByName(GoGCPauseSeconds))
+
+	// A second collection only emits cumulative series that moved
+	runtime.GC()
+	c.Collect()
+	assert.Equal(t, 2, collector.CountMetricsByName(GoGCCyclesTotal))
+
+	var total float64
+	for _, m := range collector.GetMetricsByName(GoGCCyclesTotal) {
+		value, _ := m.Value.(float64)
+		total += value
+	}
+	assert.GreaterOrEqual(t, total, 2.0, "counter deltas should sum to the cumulative GC count")
+}
+
+func TestProcessCollector(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("process stats are only collected on linux")
+	}
+	collector := setupTelemetry(t)
+
+	NewProcessCollector().Collect()
+
+	rss := collector.GetMetricsByName(ProcessResidentMemoryBytes)
+	require.Len(t, rss, 1)
+	value, _ := rss[0].Value.(float64)
+	assert.Greater(t, value, 0.0)
+	assert.Equal(t, 1, collector.CountMetricsByName(ProcessOpenFDs))
+	assert.Equal(t, 1, collector.CountMetricsByName(ProcessThreads))
+}
+
+func TestSetBuildInfo(t *testing.T) {
+	collector := setupTelemetry(t)
+
+	SetBuildInfo("1.2.3", "abc123")
+
+	info := collector.GetMetricsByName(BuildInfo)
+	require.Len(t, info, 1)
+	assert.Equal(t, "1.2.3", info[0].Tags["version"])
+	assert.Equal(t, "abc123", info[0].Tags["commit"])
+	assert.Equal(t, runtime.Version(), info[0].Tags["go_version"])
+	assert.Contains(t, info[0].Tags, "gofulmen")
+	assert.Contains(t, info[0].Tags, "crucible")
+}
@@ -0,0 +1,121 @@
+package observability
+
+import (
+	"sync"
+	"time"
+
+	"github.com/fulmenhq/gofulmen/telemetry"
+)
+
+const (
+	// DefaultMaxLabelValues is the default number of distinct values kept per metric label
+	DefaultMaxLabelValues = 100
+
+	// OverflowLabelValue replaces label values once a label reaches its limit
+	OverflowLabelValue = "__overflow__"
+
+	// CardinalityOverflowMetric counts observations whose label value was collapsed
+	CardinalityOverflowMetric = "metrics_cardinality_overflow_total"
+)
+
+// CardinalityLimiter is a telemetry.MetricsEmitter that caps the number of
+// distinct values each label of each metric may take. Values seen after the
+// limit is reached collapse to OverflowLabelValue, and every collapsed
+// observation increments CardinalityOverflowMetric{metric,label}.
+type CardinalityLimiter struct {
+	next      telemetry.MetricsEmitter
+	maxValues int
+
+	mu   sync.Mutex
+	seen map[string]map[string]map[string]struct{} // metric → label → values
+}
+
+// NewCardinalityLimiter wraps next. maxValues <= 0 disables limiting.
+func NewCardinalityLimiter(next telemetry.MetricsEmitter, maxValues int) *CardinalityLimiter {
+	return &CardinalityLimiter{
+		next:      next,
+		maxValues: maxValues,
+		seen:      make(map[string]map[string]map[string]struct{}),
+	}
+}
+
+// SetMaxValues changes the per-label limit; values already admitted are kept
+func (l *CardinalityLimiter) SetMaxValues(maxValues int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxValues = maxValues
+}
+
+// Counter implements telemetry.MetricsEmitter
+func (l *CardinalityLimiter) Counter(name string, value float64, tags map[string]string) error {
+	return l.next.Counter(name, value, l.limit(name, tags))
+}
+
+// Histogram implements telemetry.MetricsEmitter
+func (l *CardinalityLimiter) Histogram(name string, duration time.Duration, tags map[string]string) error {
+	return l.next.Histogram(name, duration, l.limit(name, tags))
+}
+
+// HistogramSummary implements telemetry.MetricsEmitter
+func (l *CardinalityLimiter) HistogramSummary(name string, summary telemetry.HistogramSummary, tags map[string]string) error {
+	return l.next.HistogramSummary(name, summary, l.limit(name, tags))
+}
+
+// Gauge implements telemetry.MetricsEmitter
+func (l *CardinalityLimiter) Gauge(name string, value float64, tags map[string]string) error {
+	return l.next.Gauge(name, value, l.limit(name, tags))
+}
+
+// limit returns tags with over-limit values collapsed. The input map is
+// never modified; a copy is made only when a value has to be replaced.
+func (l *CardinalityLimiter) limit(name string, tags map[string]string) map[string]string {
+	if len(tags) == 0 || name == CardinalityOverflowMetric {
+		return tags
+	}
+
+	var overflowed []string
+
+	l.mu.Lock()
+	if l.maxValues <= 0 {
+		l.mu.Unlock()
+		return tags
+	}
+	labels, ok := l.seen[name]
+	if !ok {
+		labels = make(map[string]map[string]struct{})
+		l.seen[name] = labels
+	}
+	for label, value := range tags {
+		values, ok := labels[label]
+		if !ok {
+			values = make(map[string]struct{})
+			labels[label] = values
+		}
+		if _, known := values[value]; known {
+			continue
+		}
+		if len(values) < l.maxValues {
+			values[value] = struct{}{}
+			continue
+		}
+		overflowed = append(overflowed, label)
+	}
+	l.mu.Unlock()
+
+	if len(overflowed) == 0 {
+		return tags
+	}
+
+	limited := make(map[string]string, len(tags))
+	for label, value := range tags {
+		limited[label] = value
+	}
+	for _, label := range overflowed {
+		limited[label] = OverflowLabelValue
+		_ = l.next.Counter(CardinalityOverflowMetric, 1, map[string]string{
+			"metric": name,
+			"label":  label,
+		})
+	}
+	return limited
+}
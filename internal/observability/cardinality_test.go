@@ -0,0 +1,93 @@
+package observability_test
+
+import (
+	"fmt"
+	"testing"
+
+	telemetrytesting "github.com/fulmenhq/gofulmen/telemetry/testing"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+func TestCardinalityLimiterCollapsesOverflow(t *testing.T) {
+	collector := telemetrytesting.NewFakeCollector()
+	limiter := observability.NewCardinalityLimiter(collector, 2)
+
+	for i := 0; i < 5; i++ {
+		tags := map[string]string{"endpoint": fmt.Sprintf("/scan/%d", i), "method": "GET"}
+		if err := limiter.Counter("errors_by_endpoint", 1, tags); err != nil {
+			t.Fatalf("counter failed: %v", err)
+		}
+		if tags["endpoint"] != fmt.Sprintf("/scan/%d", i) {
+			t.Fatal("limiter must not modify the caller's tags")
+		}
+	}
+
+	endpoints := map[string]int{}
+	for _, m := range collector.GetMetricsByName("errors_by_endpoint") {
+		endpoints[m.Tags["endpoint"]]++
+		if m.Tags["method"] != "GET" {
+			t.Fatalf("labels under the limit must be kept, got %v", m.Tags)
+		}
+	}
+	if len(endpoints) != 3 || endpoints[observability.OverflowLabelValue] != 3 {
+		t.Fatalf("expected 2 admitted values plus 3 overflowed observations, got %v", endpoints)
+	}
+
+	overflow := collector.GetMetricsByName(observability.CardinalityOverflowMetric)
+	if len(overflow) != 3 {
+		t.Fatalf("expected 3 overflow counter increments, got %d", len(overflow))
+	}
+	if overflow[0].Tags["metric"] != "errors_by_endpoint" || overflow[0].Tags["label"] != "endpoint" {
+		t.Fatalf("unexpected overflow labels: %v", overflow[0].Tags)
+	}
+
+	// Values admitted before the limit was reached keep flowing through
+	_ = limiter.Counter("errors_by_endpoint", 1, map[string]string{"endpoint": "/scan/0", "method": "GET"})
+	last := collector.GetMetricsByName("errors_by_endpoint")
+	if got := last[len(last)-1].Tags["endpoint"]; got != "/scan/0" {
+		t.Fatalf("expected admitted value to pass through, got %s", got)
+	}
+}
+
+func TestCardinalityLimiterDisabled(t *testing.T) {
+	collector := telemetrytesting.NewFakeCollector()
+	limiter := observability.NewCardinalityLimiter(collector, 0)
+
+	for i := 0; i < 10; i++ {
+		_ = limiter.Gauge("g", 1, map[string]string{"id": fmt.Sprint(i)})
+	}
+	if n := collector.CountMetricsByName(observability.CardinalityOverflowMetric); n != 0 {
+		t.Fatalf("expected no overflow when disabled, got %d", n)
+	}
+}
+
+func TestSetMaxLabelValuesZeroUsesDefault(t *testing.T) {
+	observability.SetMaxLabelValues(0)
+	if err := observability.InitMetrics("test", 0, "test"); err != nil {
+		t.Fatalf("failed to init metrics: %v", err)
+	}
+	t.Cleanup(func() {
+		observability.MetricsRegistry = nil
+		observability.TelemetrySystem = nil
+	})
+
+	for i := 0; i <= observability.DefaultMaxLabelValues; i++ {
+		_ = observability.TelemetrySystem.Gauge("g", 1, map[string]string{"id": fmt.Sprint(i)})
+	}
+
+	families, err := observability.Metrics()
+	if err != nil {
+		t.Fatalf("Metrics failed: %v", err)
+	}
+	for _, family := range families {
+		if family.Name != "test_g" {
+			continue
+		}
+		if len(family.Series) != observability.DefaultMaxLabelValues+1 {
+			t.Fatalf("expected %d values plus overflow, got %d series", observability.DefaultMaxLabelValues, len(family.Series))
+		}
+		return
+	}
+	t.Fatal("gauge test_g not exported")
+}
@@ -0,0 +1,35 @@
+package observability
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// loggerContextKey is a custom type to avoid context key collisions
+type loggerContextKey struct{}
+
+// nopLogger is returned by FromContext before the server logger exists
+var nopLogger = &Logger{zap: zap.NewNop(), levels: newLevelControl(zapcore.InfoLevel)}
+
+// WithLogger returns a copy of ctx carrying logger
+func WithLogger(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx. Inside an HTTP request that is
+// the request-scoped logger, already bound with request_id, method, route,
+// client_ip and trace_id. Otherwise it is ServerLogger, or a no-op logger
+// before ServerLogger is initialized, so callers never need a nil check.
+func FromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerContextKey{}).(*Logger); ok && logger != nil {
+			return logger
+		}
+	}
+	if ServerLogger != nil {
+		return ServerLogger
+	}
+	return nopLogger
+}
@@ -0,0 +1,72 @@
+package observability
+
+import (
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// maxExemplarLabelRunes is the OpenMetrics limit on the combined length of
+// exemplar label names and values
+const maxExemplarLabelRunes = 128
+
+// Exemplar links a histogram observation to the request that produced it
+type Exemplar struct {
+	Labels    map[string]string
+	Value     float64
+	Timestamp time.Time
+}
+
+// exemplarKey identifies one bucket of one histogram series
+type exemplarKey struct {
+	name   string
+	series string
+	le     float64
+}
+
+var (
+	exemplarsMu sync.RWMutex
+
+	// exemplars keeps the most recent exemplar per histogram bucket
+	exemplars = make(map[exemplarKey]Exemplar)
+)
+
+// SeriesKey returns the key that identifies a label set when rendering.
+// Callers recording exemplars on a hot path should compute it once per series.
+func SeriesKey(tags map[string]string) string {
+	return promLabels(tags, "", "")
+}
+
+// RecordExemplar stores ex as the latest exemplar for the bucket le of the
+// histogram series name{series}. Exemplars are only rendered in OpenMetrics
+// output; exemplars whose labels exceed the OpenMetrics size limit are dropped.
+func RecordExemplar(name, series string, le float64, ex Exemplar) {
+	if TelemetrySystem == nil || len(ex.Labels) == 0 || !exemplarLabelsFit(ex.Labels) {
+		return
+	}
+
+	exemplarsMu.Lock()
+	defer exemplarsMu.Unlock()
+	exemplars[exemplarKey{name: name, series: series, le: le}] = ex
+}
+
+func lookupExemplar(name, series string, le float64) (Exemplar, bool) {
+	exemplarsMu.RLock()
+	defer exemplarsMu.RUnlock()
+	ex, ok := exemplars[exemplarKey{name: name, series: series, le: le}]
+	return ex, ok
+}
+
+func resetExemplars() {
+	exemplarsMu.Lock()
+	defer exemplarsMu.Unlock()
+	exemplars = make(map[exemplarKey]Exemplar)
+}
+
+func exemplarLabelsFit(labels map[string]string) bool {
+	runes := 0
+	for name, value := range labels {
+		runes += utf8.RuneCountInString(name) + utf8.RuneCountInString(value)
+	}
+	return runes <= maxExemplarLabelRunes
+}
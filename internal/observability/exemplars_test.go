@@ -0,0 +1,88 @@
+package observability_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fulmenhq/gofulmen/telemetry"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	for accept, want := range map[string]observability.ExpositionFormat{
+		"":                                  observability.FormatPrometheus,
+		"text/plain;version=0.0.4":          observability.FormatPrometheus,
+		"application/openmetrics-text":      observability.FormatOpenMetrics,
+		"application/openmetrics-text; q=0": observability.FormatPrometheus,
+		"application/openmetrics-text;version=1.0.0,application/openmetrics-text;version=0.0.1;q=0.75,text/plain;version=0.0.4;q=0.5,*/*;q=0.1": observability.FormatOpenMetrics,
+	} {
+		if got := observability.NegotiateFormat(accept); got != want {
+			t.Fatalf("NegotiateFormat(%q) = %v, want %v", accept, got, want)
+		}
+	}
+}
+
+func TestWriteOpenMetricsAttachesExemplars(t *testing.T) {
+	if err := observability.InitMetrics("test", 0, "test"); err != nil {
+		t.Fatalf("failed to init metrics: %v", err)
+	}
+	t.Cleanup(func() {
+		observability.MetricsRegistry = nil
+		observability.TelemetrySystem = nil
+	})
+
+	tags := map[string]string{"endpoint": "/items"}
+	_ = observability.TelemetrySystem.Counter("http_requests_total", 1, tags)
+	_ = observability.TelemetrySystem.HistogramSummary("http_request_duration_ms", telemetry.HistogramSummary{
+		Count: 1,
+		Sum:   42,
+		Buckets: []telemetry.HistogramBucket{
+			{LE: 10, Count: 0},
+			{LE: 50, Count: 1},
+		},
+	}, tags)
+
+	series := observability.SeriesKey(tags)
+	observability.RecordExemplar("http_request_duration_ms", series, 50, observability.Exemplar{
+		Labels:    map[string]string{"request_id": "req-1"},
+		Value:     42,
+		Timestamp: time.Unix(1700000000, 500*int64(time.Millisecond)),
+	})
+	// Oversized exemplar labels violate OpenMetrics and are dropped
+	observability.RecordExemplar("http_request_duration_ms", series, 10, observability.Exemplar{
+		Labels: map[string]string{"request_id": strings.Repeat("x", 200)},
+		Value:  5,
+	})
+
+	var buf bytes.Buffer
+	if err := observability.WriteOpenMetrics(&buf, observability.MetricsRegistry, "test"); err != nil {
+		t.Fatalf("WriteOpenMetrics failed: %v", err)
+	}
+	body := buf.String()
+
+	for _, want := range []string{
+		"# TYPE test_http_requests counter\n",
+		`test_http_requests_total{endpoint="/items"} 1` + "\n",
+		`test_http_request_duration_ms_bucket{endpoint="/items",le="10"} 0` + "\n",
+		`test_http_request_duration_ms_bucket{endpoint="/items",le="50"} 1 # {request_id="req-1"} 42 1700000000.500` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected OpenMetrics output to contain %q, got:\n%s", want, body)
+		}
+	}
+	if !strings.HasSuffix(body, "# EOF\n") {
+		t.Fatalf("expected output to end with # EOF, got:\n%s", body)
+	}
+
+	// The Prometheus text format has no exemplar syntax
+	buf.Reset()
+	if err := observability.WritePrometheus(&buf, observability.MetricsRegistry, "test"); err != nil {
+		t.Fatalf("WritePrometheus failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "request_id") || strings.Contains(buf.String(), "# EOF") {
+		t.Fatalf("expected plain Prometheus output, got:\n%s", buf.String())
+	}
+}
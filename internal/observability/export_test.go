@@ -0,0 +1,18 @@
+package observability
+
+import "io"
+
+// SetLogOutput points console log sinks at w until the returned func is called
+func SetLogOutput(w io.Writer) (restore func()) {
+	previous := logOutput
+	logOutput = w
+	return func() { logOutput = previous }
+}
+
+// SetShipErrorOutput points http and syslog sink delivery reports at w
+// until the returned func is called
+func SetShipErrorOutput(w io.Writer) (restore func()) {
+	previous := shipErrorOutput
+	shipErrorOutput = w
+	return func() { shipErrorOutput = previous }
+}
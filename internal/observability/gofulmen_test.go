@@ -7,6 +7,7 @@ import (
 	"github.com/fulmenhq/gofulmen/logging"
 	"go.uber.org/zap"
 
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
 	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
 )
 
@@ -27,7 +28,9 @@ func TestGofulmenIntegration(t *testing.T) {
 
 	t.Run("Structured logger creation", func(t *testing.T) {
 		// Initialize server logger
-		observability.InitServerLogger("test-service", "info")
+		if err := observability.InitServerLogger("test-service", config.LoggingConfig{Level: "info"}); err != nil {
+			t.Fatalf("Failed to initialize server logger: %v", err)
+		}
 
 		if observability.ServerLogger == nil {
 			t.Fatal("Server logger should not be nil after initialization")
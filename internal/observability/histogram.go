@@ -0,0 +1,86 @@
+package observability
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/fulmenhq/gofulmen/telemetry"
+)
+
+// DefaultSizeBucketsBytes are the default boundaries for request/response size histograms
+var DefaultSizeBucketsBytes = []float64{100, 1000, 10000, 100000, 1000000, 10000000}
+
+var (
+	bucketsMu sync.RWMutex
+
+	// histogramBuckets holds per-metric bucket overrides from metrics.buckets
+	histogramBuckets map[string][]float64
+)
+
+// SetHistogramBuckets installs per-metric bucket boundaries (metric name →
+// upper bounds). Bounds must be positive and strictly increasing. Passing nil
+// clears all overrides.
+func SetHistogramBuckets(buckets map[string][]float64) error {
+	validated := make(map[string][]float64, len(buckets))
+	for name, bounds := range buckets {
+		if len(bounds) == 0 {
+			return fmt.Errorf("metrics.buckets.%s: at least one bucket is required", name)
+		}
+		for i, le := range bounds {
+			if le <= 0 || math.IsInf(le, 0) || math.IsNaN(le) {
+				return fmt.Errorf("metrics.buckets.%s: bucket %v must be a positive finite number", name, le)
+			}
+			if i > 0 && le <= bounds[i-1] {
+				return fmt.Errorf("metrics.buckets.%s: buckets must be strictly increasing", name)
+			}
+		}
+		validated[name] = append([]float64(nil), bounds...)
+	}
+
+	bucketsMu.Lock()
+	defer bucketsMu.Unlock()
+	histogramBuckets = validated
+	return nil
+}
+
+// HistogramBuckets returns the configured buckets for a metric, or defaults
+func HistogramBuckets(name string, defaults []float64) []float64 {
+	bucketsMu.RLock()
+	defer bucketsMu.RUnlock()
+	if bounds, ok := histogramBuckets[name]; ok {
+		return bounds
+	}
+	return defaults
+}
+
+// ObserveHistogram records a single observation against the buckets
+// configured for name. Durations are observed in milliseconds, matching the
+// gofulmen `_ms` convention.
+func ObserveHistogram(name string, value float64, defaults []float64, tags map[string]string) {
+	sys := TelemetrySystem
+	if sys == nil {
+		return
+	}
+
+	bounds := HistogramBuckets(name, defaults)
+	summary := telemetry.HistogramSummary{
+		Count:   1,
+		Sum:     value,
+		Buckets: make([]telemetry.HistogramBucket, 0, len(bounds)+1),
+	}
+
+	// Bounds are sorted, so the first matching bucket and every bucket above it count the sample
+	first := sort.SearchFloat64s(bounds, value)
+	for i, le := range bounds {
+		var count int64
+		if i >= first {
+			count = 1
+		}
+		summary.Buckets = append(summary.Buckets, telemetry.HistogramBucket{LE: le, Count: count})
+	}
+	summary.Buckets = append(summary.Buckets, telemetry.HistogramBucket{LE: math.Inf(1), Count: 1})
+
+	_ = sys.HistogramSummary(name, summary, tags)
+}
@@ -0,0 +1,73 @@
+package observability_test
+
+import (
+	"testing"
+
+	"github.com/fulmenhq/gofulmen/telemetry"
+	telemetrytesting "github.com/fulmenhq/gofulmen/telemetry/testing"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+func TestSetHistogramBucketsValidates(t *testing.T) {
+	t.Cleanup(func() { _ = observability.SetHistogramBuckets(nil) })
+
+	for name, bounds := range map[string][]float64{
+		"empty":      {},
+		"negative":   {-1, 10},
+		"unordered":  {10, 5},
+		"duplicated": {5, 5},
+	} {
+		if err := observability.SetHistogramBuckets(map[string][]float64{"m": bounds}); err == nil {
+			t.Fatalf("expected %s buckets to be rejected", name)
+		}
+	}
+
+	if err := observability.SetHistogramBuckets(map[string][]float64{"m": {1, 2, 3}}); err != nil {
+		t.Fatalf("expected valid buckets to be accepted: %v", err)
+	}
+	if got := observability.HistogramBuckets("m", nil); len(got) != 3 {
+		t.Fatalf("expected configured buckets, got %v", got)
+	}
+	if got := observability.HistogramBuckets("other", []float64{42}); len(got) != 1 || got[0] != 42 {
+		t.Fatalf("expected defaults for unconfigured metric, got %v", got)
+	}
+}
+
+func TestObserveHistogramUsesConfiguredBuckets(t *testing.T) {
+	collector := telemetrytesting.NewFakeCollector()
+	sys, err := telemetry.NewSystem(&telemetry.Config{Enabled: true, Emitter: collector})
+	if err != nil {
+		t.Fatalf("failed to create telemetry system: %v", err)
+	}
+	original := observability.TelemetrySystem
+	observability.TelemetrySystem = sys
+	t.Cleanup(func() {
+		observability.TelemetrySystem = original
+		_ = observability.SetHistogramBuckets(nil)
+	})
+
+	if err := observability.SetHistogramBuckets(map[string][]float64{"payload_bytes": {10, 100, 1000}}); err != nil {
+		t.Fatalf("failed to set buckets: %v", err)
+	}
+	observability.ObserveHistogram("payload_bytes", 100, []float64{1}, nil)
+
+	events := collector.GetMetricsByName("payload_bytes")
+	if len(events) != 1 {
+		t.Fatalf("expected one histogram event, got %d", len(events))
+	}
+	summary, ok := events[0].Value.(telemetry.HistogramSummary)
+	if !ok {
+		t.Fatalf("expected histogram summary, got %T", events[0].Value)
+	}
+
+	want := []int64{0, 1, 1, 1} // le=10, le=100 (inclusive), le=1000, +Inf
+	if len(summary.Buckets) != len(want) {
+		t.Fatalf("expected %d buckets, got %+v", len(want), summary.Buckets)
+	}
+	for i, bucket := range summary.Buckets {
+		if bucket.Count != want[i] {
+			t.Fatalf("bucket le=%v: expected count %d, got %d", bucket.LE, want[i], bucket.Count)
+		}
+	}
+}
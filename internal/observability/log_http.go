@@ -0,0 +1,602 @@
+package observability
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+)
+
+// Defaults for http sinks
+const (
+	defaultShipBatchSize     = 500
+	defaultShipFlushInterval = time.Second
+	defaultShipTimeout       = 5 * time.Second
+	defaultShipBufferMB      = 64
+)
+
+const (
+	// shipAttempts is how often a batch is sent before it is spilled to disk
+	shipAttempts = 3
+
+	// shipRetryDelay is the wait before the first retry; it doubles per attempt
+	shipRetryDelay = 250 * time.Millisecond
+
+	// shipMaxBackoff caps the wait between flushes while the endpoint is down
+	shipMaxBackoff = time.Minute
+
+	// shipMaxPending is how many full batches may queue in memory before the
+	// spill goroutine moves them to disk (or drops them without a buffer);
+	// at twice as many the oldest are dropped
+	shipMaxPending = 4
+
+	// spoolExt names the batch files in a disk buffer
+	spoolExt = ".ndjson"
+)
+
+// shipErrorOutput receives delivery problems, which http and syslog sinks
+// cannot log through the logger they belong to; tests swap it for a buffer
+var shipErrorOutput io.Writer = os.Stderr
+
+// httpShipper is the writer of an http sink. Entries are batched in memory
+// and POSTed as NDJSON by a background loop, every flush interval or once a
+// batch is full. A failed batch is retried with backoff, then spilled to the
+// disk buffer; buffered batches go out first, oldest first, once the
+// endpoint accepts requests again. Write never does I/O: full batches queue
+// for the delivery loop, and a spill goroutine moves them to disk while the
+// endpoint is not keeping up.
+type httpShipper struct {
+	endpoint  string
+	headers   map[string]string
+	client    *http.Client
+	batchSize int
+	interval  time.Duration
+	timeout   time.Duration
+
+	// spool is nil without buffer_path
+	spool *logSpool
+
+	mu      sync.Mutex
+	pending bytes.Buffer
+	count   int
+	closed  bool
+	// full are batches cut from pending, oldest first
+	full []shipBatch
+	// seq orders batches cut in the same nanosecond
+	seq int
+
+	// overflow counts entries dropped from full, reported by the loops
+	overflow atomic.Int64
+
+	// sendMu serializes deliveries from the loop, Sync and close
+	sendMu  sync.Mutex
+	failing bool
+
+	// spillMu is held while full batches are written to disk, so a delivery
+	// never overtakes older batches still being spilled
+	spillMu sync.Mutex
+
+	kick      chan struct{}
+	spillKick chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	spillDone chan struct{}
+}
+
+// shipBatch is a batch of NDJSON entries. created and seq order batches in
+// the disk buffer whichever goroutine spills them.
+type shipBatch struct {
+	data    []byte
+	entries int
+	created time.Time
+	seq     int
+}
+
+// newHTTPShipper validates the sink, opens its disk buffer and starts the
+// delivery loop
+func newHTTPShipper(sink config.LogSinkConfig) (*httpShipper, error) {
+	u, err := url.Parse(sink.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("http sink endpoint must be an http(s) URL, got %q", sink.Endpoint)
+	}
+	if sink.BatchSize < 0 || sink.FlushInterval < 0 || sink.Timeout < 0 || sink.BufferMaxMB < 0 {
+		return nil, fmt.Errorf("http sink %s: batch_size, flush_interval, timeout and buffer_max_mb must not be negative", sink.Endpoint)
+	}
+	if sink.Format != "" && sink.Format != "json" {
+		return nil, fmt.Errorf("http sink %s sends NDJSON; format must be json", sink.Endpoint)
+	}
+
+	s := &httpShipper{
+		endpoint:  sink.Endpoint,
+		headers:   sink.Headers,
+		batchSize: sink.BatchSize,
+		interval:  durationOr(sink.FlushInterval, defaultShipFlushInterval),
+		timeout:   durationOr(sink.Timeout, defaultShipTimeout),
+		kick:      make(chan struct{}, 1),
+		spillKick: make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+		spillDone: make(chan struct{}),
+	}
+	if s.batchSize == 0 {
+		s.batchSize = defaultShipBatchSize
+	}
+	s.client = &http.Client{Timeout: s.timeout}
+
+	if sink.BufferPath != "" {
+		maxMB := sink.BufferMaxMB
+		if maxMB == 0 {
+			maxMB = defaultShipBufferMB
+		}
+		if s.spool, err = openLogSpool(sink.BufferPath, int64(maxMB)<<20); err != nil {
+			return nil, fmt.Errorf("http sink %s: %w", sink.Endpoint, err)
+		}
+	}
+
+	go s.run()
+	go s.spillLoop()
+	return s, nil
+}
+
+// Write queues one encoded entry. It only appends to memory; delivery and
+// disk I/O happen on the shipper's goroutines.
+func (s *httpShipper) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	if s.closed {
+		// Entries logged after the final flush go straight to the buffer;
+		// the loops are stopped by then
+		batch := s.newBatch(bytes.Clone(p), 1)
+		s.mu.Unlock()
+		if s.spool != nil {
+			s.spill(batch)
+		}
+		return len(p), nil
+	}
+
+	s.pending.Write(p)
+	s.count++
+	var kick, spill bool
+	if s.count >= s.batchSize {
+		s.full = append(s.full, s.takePending())
+		if len(s.full) > 2*shipMaxPending {
+			// Neither delivery nor the disk keeps up; drop the oldest batch
+			s.overflow.Add(int64(s.full[0].entries))
+			s.full = s.full[1:]
+		}
+		kick, spill = true, len(s.full) >= shipMaxPending
+	}
+	s.mu.Unlock()
+
+	if kick {
+		notify(s.kick)
+	}
+	if spill {
+		notify(s.spillKick)
+	}
+	return len(p), nil
+}
+
+// notify wakes a loop without blocking
+func notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// Sync delivers the queued entries and the disk buffer
+func (s *httpShipper) Sync() error {
+	s.mu.Lock()
+	closed := s.closed
+	s.mu.Unlock()
+	if closed {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	return s.deliver(ctx)
+}
+
+// close stops the loop and makes a final delivery within ctx. Entries that
+// cannot be sent stay in the disk buffer for the next start.
+func (s *httpShipper) close(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	close(s.stop)
+	<-s.done
+	<-s.spillDone
+
+	err := s.deliver(ctx)
+	if err == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	batches := s.takeAll()
+	s.mu.Unlock()
+	for _, batch := range batches {
+		s.spill(batch)
+	}
+
+	if s.spool == nil {
+		return fmt.Errorf("http sink %s: undelivered entries dropped: %w", s.endpoint, err)
+	}
+	return fmt.Errorf("http sink %s: %d entries kept in %s for the next start: %w",
+		s.endpoint, s.spool.entries(), s.spool.dir, err)
+}
+
+// run delivers every flush interval or when a batch is full, backing off
+// while the endpoint fails
+func (s *httpShipper) run() {
+	defer close(s.done)
+
+	var backoff time.Duration
+	timer := time.NewTimer(s.interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-s.kick:
+			if backoff > 0 {
+				// Full batches spill to disk until the timer retries
+				notify(s.spillKick)
+				continue
+			}
+		case <-timer.C:
+		}
+
+		if err := s.deliver(context.Background()); err != nil && !permanentShipError(err) {
+			backoff = min(max(2*backoff, s.interval), shipMaxBackoff)
+		} else {
+			backoff = 0
+		}
+		timer.Reset(max(s.interval, backoff))
+	}
+}
+
+// spillLoop moves full batches to the disk buffer while they pile up, so
+// Write never waits for the disk
+func (s *httpShipper) spillLoop() {
+	defer close(s.spillDone)
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-s.spillKick:
+		}
+
+		s.spillMu.Lock()
+		s.mu.Lock()
+		batches := s.full
+		s.full = nil
+		s.mu.Unlock()
+		for _, batch := range batches {
+			s.spill(batch)
+		}
+		s.spillMu.Unlock()
+		s.reportOverflow()
+	}
+}
+
+// deliver sends the disk buffer, then the queued batches. It stops at the
+// first batch the endpoint does not accept, so order is kept.
+func (s *httpShipper) deliver(ctx context.Context) error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	s.reportOverflow()
+
+	// Wait for batches being spilled, which are older than those taken here
+	s.spillMu.Lock()
+	s.mu.Lock()
+	batches := s.takeAll()
+	s.mu.Unlock()
+	s.spillMu.Unlock()
+
+	if s.spool != nil {
+		for {
+			segment, data, ok, err := s.spool.oldest()
+			if err != nil {
+				s.report("dropping unreadable buffer file: %v", err)
+				continue
+			}
+			// Batches spilled since the take are newer than those in hand
+			if !ok || (len(batches) > 0 && segment.name >= batches[0].name()) {
+				break
+			}
+			if err := s.send(ctx, data); err != nil {
+				if !permanentShipError(err) {
+					for _, batch := range batches {
+						s.spill(batch)
+					}
+					return s.failed(err)
+				}
+				s.report("dropping %d buffered entries: %v", segment.entries, err)
+			}
+			s.spool.remove(segment)
+		}
+	}
+
+	var rejected error
+	for i, batch := range batches {
+		err := s.send(ctx, batch.data)
+		switch {
+		case err == nil:
+		case permanentShipError(err):
+			s.report("dropping %d entries: %v", batch.entries, err)
+			rejected = err
+		default:
+			for _, rest := range batches[i:] {
+				s.spill(rest)
+			}
+			return s.failed(err)
+		}
+	}
+	s.recovered()
+	return rejected
+}
+
+// send POSTs one batch, retrying with exponential backoff unless the
+// endpoint rejects it outright
+func (s *httpShipper) send(ctx context.Context, batch []byte) error {
+	var err error
+	for attempt := range shipAttempts {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return errors.Join(err, ctx.Err())
+			case <-time.After(shipRetryDelay << (attempt - 1)):
+			}
+		}
+		err = postPayload(ctx, s.client, s.endpoint, s.headers, batch, map[string]string{
+			"Content-Type": "application/x-ndjson",
+		})
+		if err == nil || permanentShipError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// permanentShipError reports a client error that a retry cannot fix; 408
+// and 429 are worth retrying
+func permanentShipError(err error) bool {
+	var statusErr *httpStatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	return statusErr.code >= 400 && statusErr.code < 500 &&
+		statusErr.code != http.StatusRequestTimeout && statusErr.code != http.StatusTooManyRequests
+}
+
+// newBatch stamps a batch with its place in the delivery order; s.mu must
+// be held
+func (s *httpShipper) newBatch(data []byte, entries int) shipBatch {
+	s.seq++
+	return shipBatch{data: data, entries: entries, created: time.Now(), seq: s.seq}
+}
+
+// takePending cuts the queued entries into a batch; s.mu must be held
+func (s *httpShipper) takePending() shipBatch {
+	batch := s.newBatch(bytes.Clone(s.pending.Bytes()), s.count)
+	s.pending.Reset()
+	s.count = 0
+	return batch
+}
+
+// takeAll returns the full batches and the queued entries, oldest first,
+// and empties the queue; s.mu must be held
+func (s *httpShipper) takeAll() []shipBatch {
+	batches := s.full
+	s.full = nil
+	if s.count > 0 {
+		batches = append(batches, s.takePending())
+	}
+	return batches
+}
+
+// spill moves a batch to the disk buffer, or drops it without one. It runs
+// on the shipper's goroutines, never under s.mu.
+func (s *httpShipper) spill(batch shipBatch) {
+	if s.spool == nil {
+		s.report("dropping %d entries (no buffer_path)", batch.entries)
+		return
+	}
+	dropped, err := s.spool.add(batch)
+	if err != nil {
+		s.report("dropping %d entries: %v", batch.entries, err)
+	}
+	if dropped > 0 {
+		s.report("buffer full, dropped the %d oldest entries", dropped)
+	}
+}
+
+// reportOverflow reports entries dropped because too many batches queued
+func (s *httpShipper) reportOverflow() {
+	if n := s.overflow.Swap(0); n > 0 {
+		s.report("delivery and buffer not keeping up, dropped %d entries", n)
+	}
+}
+
+// name is the batch's file name in the disk buffer; names sort in
+// delivery order
+func (b shipBatch) name() string {
+	return fmt.Sprintf("%019d-%06d%s", b.created.UnixNano(), b.seq%1000000, spoolExt)
+}
+
+// failed reports the start of an outage once; s.sendMu must be held
+func (s *httpShipper) failed(err error) error {
+	if !s.failing {
+		s.failing = true
+		s.report("delivery failed, buffering entries: %v", err)
+	}
+	return err
+}
+
+// recovered reports the end of an outage; s.sendMu must be held
+func (s *httpShipper) recovered() {
+	if s.failing {
+		s.failing = false
+		s.report("delivery recovered")
+	}
+}
+
+func (s *httpShipper) report(format string, args ...any) {
+	_, _ = fmt.Fprintf(shipErrorOutput, "log shipping to %s: %s\n", s.endpoint, fmt.Sprintf(format, args...))
+}
+
+// logSpool is the disk buffer of an http sink: one NDJSON file per batch,
+// named so they sort oldest first. Files left by a previous run are
+// delivered after the next start. When the buffer is full, the oldest
+// batches are dropped.
+type logSpool struct {
+	dir      string
+	maxBytes int64
+
+	mu       sync.Mutex
+	segments []spoolSegment
+	size     int64
+}
+
+// spoolSegment is one buffered batch; segments are kept sorted by name
+type spoolSegment struct {
+	name    string
+	path    string
+	size    int64
+	entries int
+}
+
+func openLogSpool(dir string, maxBytes int64) (*logSpool, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("buffer_path: %w", err)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+spoolExt))
+	if err != nil {
+		return nil, fmt.Errorf("buffer_path: %w", err)
+	}
+	sort.Strings(paths)
+
+	s := &logSpool{dir: dir, maxBytes: maxBytes}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("buffer_path: %w", err)
+		}
+		s.segments = append(s.segments, spoolSegment{
+			name:    filepath.Base(path),
+			path:    path,
+			size:    int64(len(data)),
+			entries: bytes.Count(data, []byte("\n")),
+		})
+		s.size += int64(len(data))
+	}
+	return s, nil
+}
+
+// add writes a batch in order, dropping the oldest batches to make room,
+// and returns the number of entries dropped
+func (s *logSpool) add(batch shipBatch) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	size := int64(len(batch.data))
+	if size > s.maxBytes {
+		return batch.entries, nil
+	}
+	dropped := 0
+	for len(s.segments) > 0 && s.size+size > s.maxBytes {
+		oldest := s.segments[0]
+		_ = os.Remove(oldest.path)
+		s.segments = s.segments[1:]
+		s.size -= oldest.size
+		dropped += oldest.entries
+	}
+
+	// Write under a temporary name so a crash never leaves half a batch
+	name := batch.name()
+	path := filepath.Join(s.dir, name)
+	if err := os.WriteFile(path+".tmp", batch.data, 0o640); err != nil {
+		return dropped, err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		_ = os.Remove(path + ".tmp")
+		return dropped, err
+	}
+
+	// A batch failed by a delivery can be older than batches spilled meanwhile
+	i := sort.Search(len(s.segments), func(i int) bool { return s.segments[i].name > name })
+	s.segments = slices.Insert(s.segments, i, spoolSegment{name: name, path: path, size: size, entries: batch.entries})
+	s.size += size
+	return dropped, nil
+}
+
+// oldest reads the oldest batch; ok is false when the buffer is empty. A
+// file that cannot be read is forgotten and returned as an error.
+func (s *logSpool) oldest() (spoolSegment, []byte, bool, error) {
+	s.mu.Lock()
+	if len(s.segments) == 0 {
+		s.mu.Unlock()
+		return spoolSegment{}, nil, false, nil
+	}
+	segment := s.segments[0]
+	s.mu.Unlock()
+
+	data, err := os.ReadFile(segment.path)
+	if err != nil {
+		s.remove(segment)
+		if errors.Is(err, os.ErrNotExist) {
+			// Dropped by add to make room while we were sending
+			return s.oldest()
+		}
+		return segment, nil, false, err
+	}
+	return segment, data, true, nil
+}
+
+// remove deletes a delivered batch
+func (s *logSpool) remove(segment spoolSegment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, candidate := range s.segments {
+		if candidate.path == segment.path {
+			s.segments = append(s.segments[:i], s.segments[i+1:]...)
+			s.size -= candidate.size
+			break
+		}
+	}
+	_ = os.Remove(segment.path)
+}
+
+// entries counts the buffered entries
+func (s *logSpool) entries() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, segment := range s.segments {
+		n += segment.entries
+	}
+	return n
+}
@@ -0,0 +1,297 @@
+package observability_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+// collector records NDJSON batches and answers with status
+type collector struct {
+	mu      sync.Mutex
+	status  int
+	batches []*bytes.Buffer
+	headers []http.Header
+	calls   int
+}
+
+func (c *collector) server(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.calls++
+		if c.status != 0 {
+			w.WriteHeader(c.status)
+			return
+		}
+		c.batches = append(c.batches, bytes.NewBuffer(body))
+		c.headers = append(c.headers, r.Header.Clone())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func (c *collector) setStatus(status int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status = status
+}
+
+// messages returns the message of every received entry, in order
+func (c *collector) messages(t *testing.T) []string {
+	t.Helper()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var messages []string
+	for _, batch := range c.batches {
+		for _, entry := range decodeLines(t, bytes.NewBuffer(batch.Bytes())) {
+			messages = append(messages, entry["message"].(string))
+		}
+	}
+	return messages
+}
+
+func newShippingLogger(t *testing.T, sink config.LogSinkConfig) *observability.Logger {
+	t.Helper()
+	sink.Type = "http"
+	if sink.FlushInterval == 0 {
+		sink.FlushInterval = time.Hour
+	}
+	logger, err := observability.NewLogger("groningen", config.LoggingConfig{
+		Level:   "info",
+		Profile: "STRUCTURED",
+		Sinks:   []config.LogSinkConfig{sink},
+	})
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+	return logger
+}
+
+func TestHTTPSinkShipsNDJSONBatches(t *testing.T) {
+	var got collector
+	srv := got.server(t)
+	logger := newShippingLogger(t, config.LogSinkConfig{
+		Endpoint:  srv.URL + "/ingest",
+		Headers:   map[string]string{"Authorization": "Bearer ingest-token"},
+		BatchSize: 2,
+	})
+
+	logger.Info("one")
+	logger.Info("two")
+	deadline := time.Now().Add(2 * time.Second)
+	for len(got.messages(t)) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if messages := got.messages(t); len(messages) != 2 {
+		t.Fatalf("a full batch should be sent right away, got %v", messages)
+	}
+
+	logger.Info("three")
+	if err := logger.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	if messages := got.messages(t); strings.Join(messages, ",") != "one,two,three" {
+		t.Errorf("messages = %v", messages)
+	}
+	if len(got.batches) != 2 {
+		t.Errorf("batches = %d, want 2", len(got.batches))
+	}
+	header := got.headers[0]
+	if header.Get("Content-Type") != "application/x-ndjson" || header.Get("Authorization") != "Bearer ingest-token" {
+		t.Errorf("headers = %v", header)
+	}
+}
+
+func TestHTTPSinkBuffersOnDiskDuringOutage(t *testing.T) {
+	var reports bytes.Buffer
+	t.Cleanup(observability.SetShipErrorOutput(&reports))
+
+	var got collector
+	srv := got.server(t)
+	got.setStatus(http.StatusServiceUnavailable)
+	dir := filepath.Join(t.TempDir(), "spool")
+	sink := config.LogSinkConfig{Endpoint: srv.URL, BufferPath: dir}
+
+	logger := newShippingLogger(t, sink)
+	logger.Info("during outage")
+	logger.Warn("still down")
+	err := logger.Flush(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "2 entries kept in "+dir) {
+		t.Fatalf("Flush error = %v, want the buffered entries reported", err)
+	}
+	if got.calls != 3 {
+		t.Errorf("attempts = %d, want 3 (with retries)", got.calls)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*.ndjson")); len(files) != 1 {
+		t.Errorf("buffer files = %v, want one batch", files)
+	}
+	if !strings.Contains(reports.String(), "delivery failed, buffering entries") {
+		t.Errorf("reports = %q", reports.String())
+	}
+
+	// After a restart the buffer is delivered first, in order
+	got.setStatus(0)
+	logger = newShippingLogger(t, sink)
+	logger.Info("after restart")
+	if err := logger.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	if messages := got.messages(t); strings.Join(messages, ",") != "during outage,still down,after restart" {
+		t.Errorf("messages = %v", messages)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) != 0 {
+		t.Errorf("delivered batches should be removed, left %v", files)
+	}
+}
+
+func TestHTTPSinkSpillsBacklogInOrder(t *testing.T) {
+	var reports bytes.Buffer
+	t.Cleanup(observability.SetShipErrorOutput(&reports))
+
+	var got collector
+	srv := got.server(t)
+	got.setStatus(http.StatusServiceUnavailable)
+	dir := filepath.Join(t.TempDir(), "spool")
+	sink := config.LogSinkConfig{Endpoint: srv.URL, BatchSize: 2, BufferPath: dir}
+
+	// Full batches pile up while the endpoint is down; the shipper's
+	// goroutines move them to disk, oldest first
+	logger := newShippingLogger(t, sink)
+	var want []string
+	for round := 1; round <= 3; round++ {
+		for i := 0; i < 8; i++ {
+			msg := fmt.Sprintf("entry %02d", len(want))
+			logger.Info(msg)
+			want = append(want, msg)
+		}
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			files, _ := filepath.Glob(filepath.Join(dir, "*.ndjson"))
+			if len(files) == 4*round {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("round %d: buffer files = %d, want %d", round, len(files), 4*round)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	err := logger.Flush(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "24 entries kept in "+dir) {
+		t.Fatalf("Flush error = %v, want the buffered entries reported", err)
+	}
+
+	got.setStatus(0)
+	logger = newShippingLogger(t, sink)
+	if err := logger.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if messages := got.messages(t); strings.Join(messages, ",") != strings.Join(want, ",") {
+		t.Errorf("messages = %v, want %v", messages, want)
+	}
+	if strings.Contains(reports.String(), "dropped") {
+		t.Errorf("reports = %q, want nothing dropped", reports.String())
+	}
+}
+
+func TestHTTPSinkBuffersEntriesLoggedDuringClose(t *testing.T) {
+	var reports bytes.Buffer
+	t.Cleanup(observability.SetShipErrorOutput(&reports))
+
+	var got collector
+	srv := got.server(t)
+	got.setStatus(http.StatusServiceUnavailable)
+	dir := filepath.Join(t.TempDir(), "spool")
+	sink := config.LogSinkConfig{Endpoint: srv.URL, BufferPath: dir}
+
+	// Entries logged while the sink closes and after it closed end up in
+	// the buffer
+	logger := newShippingLogger(t, sink)
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					logger.Info("while closing")
+				}
+			}
+		}()
+	}
+	_ = logger.Flush(context.Background())
+	close(stop)
+	wg.Wait()
+	logger.Info("after close")
+
+	got.setStatus(0)
+	logger = newShippingLogger(t, sink)
+	if err := logger.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	messages := got.messages(t)
+	if len(messages) == 0 || messages[len(messages)-1] != "after close" {
+		t.Errorf("the entry logged after close should be delivered last, got %d messages ending %v", len(messages), messages[max(0, len(messages)-1):])
+	}
+}
+
+func TestHTTPSinkDropsRejectedBatches(t *testing.T) {
+	var reports bytes.Buffer
+	t.Cleanup(observability.SetShipErrorOutput(&reports))
+
+	var got collector
+	srv := got.server(t)
+	got.setStatus(http.StatusBadRequest)
+	dir := t.TempDir()
+
+	logger := newShippingLogger(t, config.LogSinkConfig{Endpoint: srv.URL, BufferPath: dir})
+	logger.Info("malformed for this collector")
+	if err := logger.Flush(context.Background()); err == nil {
+		t.Error("Flush should report the rejected batch")
+	}
+
+	if got.calls != 1 {
+		t.Errorf("attempts = %d; a 400 must not be retried", got.calls)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("a rejected batch must not be buffered, found %d files", len(files))
+	}
+	if !strings.Contains(reports.String(), "dropping 1 entries") {
+		t.Errorf("reports = %q", reports.String())
+	}
+}
+
+func TestHTTPSinkRejectsInvalidConfig(t *testing.T) {
+	for name, sink := range map[string]config.LogSinkConfig{
+		"endpoint":   {Type: "http", Endpoint: "collector:8080"},
+		"format":     {Type: "http", Endpoint: "http://127.0.0.1:1", Format: "console"},
+		"batch size": {Type: "http", Endpoint: "http://127.0.0.1:1", BatchSize: -1},
+	} {
+		_, err := observability.NewLogger("svc", config.LoggingConfig{Sinks: []config.LogSinkConfig{sink}})
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
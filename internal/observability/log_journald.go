@@ -0,0 +1,213 @@
+package observability
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// defaultJournalSocket is systemd-journald's native protocol socket
+const defaultJournalSocket = "/run/systemd/journal/socket"
+
+// journalReserved are the journal fields the sink sets itself; log fields
+// mapping to one of them get a FIELD_ prefix instead of overriding it
+var journalReserved = map[string]bool{
+	"MESSAGE": true, "PRIORITY": true, "SYSLOG_IDENTIFIER": true,
+	"LOGGER": true, "STACKTRACE": true,
+}
+
+// journalCore writes entries to journald over its native protocol: one
+// datagram of FIELD=value lines per entry, with every log field mapped to an
+// uppercase journal field (request_id and requestId both become REQUEST_ID)
+type journalCore struct {
+	zapcore.LevelEnabler
+	writer     *journalWriter
+	identifier string
+	fields     []zapcore.Field
+}
+
+func newJournalCore(address, identifier string, enabler zapcore.LevelEnabler) (*journalCore, error) {
+	if address == "" {
+		address = defaultJournalSocket
+	}
+	conn, err := net.Dial("unixgram", address)
+	if err != nil {
+		return nil, fmt.Errorf("journald sink %s: %w", address, err)
+	}
+	return &journalCore{
+		LevelEnabler: enabler,
+		writer:       &journalWriter{address: address, conn: conn},
+		identifier:   identifier,
+	}, nil
+}
+
+func (c *journalCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	return &clone
+}
+
+func (c *journalCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *journalCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	encoded := zapcore.NewMapObjectEncoder()
+	for _, field := range c.fields {
+		field.AddTo(encoded)
+	}
+	for _, field := range fields {
+		field.AddTo(encoded)
+	}
+
+	var msg bytes.Buffer
+	appendJournalField(&msg, "MESSAGE", entry.Message)
+	appendJournalField(&msg, "PRIORITY", strconv.Itoa(syslogSeverity(entry.Level)))
+	appendJournalField(&msg, "SYSLOG_IDENTIFIER", c.identifier)
+	if entry.LoggerName != "" {
+		appendJournalField(&msg, "LOGGER", entry.LoggerName)
+	}
+	if entry.Stack != "" {
+		appendJournalField(&msg, "STACKTRACE", entry.Stack)
+	}
+
+	keys := make([]string, 0, len(encoded.Fields))
+	for key := range encoded.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		name := journalFieldName(key)
+		if name == "" {
+			continue
+		}
+		if journalReserved[name] {
+			name = "FIELD_" + name
+		}
+		appendJournalField(&msg, name, journalValue(encoded.Fields[key]))
+	}
+
+	return c.writer.write(msg.Bytes())
+}
+
+func (c *journalCore) Sync() error {
+	return nil
+}
+
+// journalFieldName maps a log field name to a journal field name: uppercase
+// letters, digits and underscores, not starting with an underscore (reserved
+// for trusted fields) or a digit, at most 64 characters. camelCase words are
+// split, so correlationId becomes CORRELATION_ID.
+func journalFieldName(key string) string {
+	var b strings.Builder
+	prevLower := false
+	for _, r := range key {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			if prevLower {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+			prevLower = false
+		case r >= 'a' && r <= 'z':
+			b.WriteRune(r - 'a' + 'A')
+			prevLower = true
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+			prevLower = true
+		default:
+			b.WriteByte('_')
+			prevLower = false
+		}
+	}
+
+	name := strings.TrimLeft(b.String(), "_")
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "F_" + name
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// journalValue formats a field value: strings and scalars as text, times in
+// RFC 3339, objects and arrays as JSON
+func journalValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case time.Duration:
+		return v.String()
+	case []byte:
+		return string(v)
+	case fmt.Stringer:
+		return v.String()
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr, float32, float64:
+		return fmt.Sprint(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+// appendJournalField encodes one field. Values containing a newline use the
+// binary form: NAME, newline, little-endian uint64 length, value, newline.
+func appendJournalField(b *bytes.Buffer, name, value string) {
+	b.WriteString(name)
+	if !strings.Contains(value, "\n") {
+		b.WriteByte('=')
+		b.WriteString(value)
+		b.WriteByte('\n')
+		return
+	}
+	b.WriteByte('\n')
+	_ = binary.Write(b, binary.LittleEndian, uint64(len(value)))
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+// journalWriter sends datagrams to journald, redialing once when a send fails
+// (journald restarted). Entries too large for a datagram are passed as a
+// sealed memfd on Linux (see sendJournal).
+type journalWriter struct {
+	mu      sync.Mutex
+	address string
+	conn    net.Conn
+}
+
+func (w *journalWriter) write(msg []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn != nil {
+		if err := sendJournal(w.conn, msg); err == nil {
+			return nil
+		}
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+	conn, err := net.Dial("unixgram", w.address)
+	if err != nil {
+		return err
+	}
+	w.conn = conn
+	return sendJournal(conn, msg)
+}
@@ -0,0 +1,61 @@
+//go:build linux
+
+package observability
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// journalSeals make a memfd immutable before journald reads it
+const journalSeals = unix.F_SEAL_SHRINK | unix.F_SEAL_GROW | unix.F_SEAL_WRITE | unix.F_SEAL_SEAL
+
+// sendJournal writes msg as one datagram. An entry larger than the socket
+// send buffer fails with EMSGSIZE; like sd_journal_sendv, it is then written
+// to a sealed memfd whose descriptor is passed in an empty datagram
+// (SCM_RIGHTS), which journald reads in place of the payload.
+func sendJournal(conn net.Conn, msg []byte) error {
+	_, err := conn.Write(msg)
+	if !errors.Is(err, syscall.EMSGSIZE) {
+		return err
+	}
+	sysConn, ok := conn.(syscall.Conn)
+	if !ok {
+		return err
+	}
+
+	fd, err := unix.MemfdCreate("journal-entry", unix.MFD_CLOEXEC|unix.MFD_ALLOW_SEALING)
+	if err != nil {
+		return fmt.Errorf("journald entry of %d bytes: memfd: %w", len(msg), err)
+	}
+	defer func() { _ = unix.Close(fd) }()
+
+	for written := 0; written < len(msg); {
+		n, err := unix.Write(fd, msg[written:])
+		if err != nil {
+			return fmt.Errorf("journald entry of %d bytes: memfd write: %w", len(msg), err)
+		}
+		written += n
+	}
+	if _, err := unix.FcntlInt(uintptr(fd), unix.F_ADD_SEALS, journalSeals); err != nil {
+		return fmt.Errorf("journald entry of %d bytes: memfd seal: %w", len(msg), err)
+	}
+
+	// net refuses WriteMsgUnix on a connected datagram socket, so send directly
+	raw, err := sysConn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var sendErr error
+	if err := raw.Write(func(s uintptr) bool {
+		sendErr = unix.Sendmsg(int(s), nil, unix.UnixRights(fd), nil, 0)
+		return !errors.Is(sendErr, unix.EAGAIN)
+	}); err != nil {
+		return err
+	}
+	return sendErr
+}
@@ -0,0 +1,76 @@
+//go:build linux
+
+package observability_test
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+func TestJournaldSinkPassesLargeEntriesAsMemfd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	logger, err := observability.NewLogger("groningen", config.LoggingConfig{
+		Level:   "info",
+		Profile: "ENTERPRISE",
+		Sinks:   []config.LogSinkConfig{{Type: "journald", Address: path}},
+	})
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+
+	// Far above the default unix socket send buffer (net.core.wmem_default)
+	payload := strings.Repeat("x", 4<<20)
+	logger.Info("large entry", zap.String("payload", payload))
+
+	buf := make([]byte, 1024)
+	oob := make([]byte, syscall.CmsgSpace(4))
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Fatalf("expected an empty datagram carrying a descriptor, got %d bytes", n)
+	}
+
+	messages, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if err != nil || len(messages) != 1 {
+		t.Fatalf("expected one control message, got %d (%v)", len(messages), err)
+	}
+	fds, err := syscall.ParseUnixRights(&messages[0])
+	if err != nil || len(fds) != 1 {
+		t.Fatalf("expected one descriptor, got %v (%v)", fds, err)
+	}
+	file := os.NewFile(uintptr(fds[0]), "journal-entry")
+	defer func() { _ = file.Close() }()
+
+	// journald maps the memfd; read it from the start like it does
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := parseJournal(t, data)
+	if fields["MESSAGE"] != "large entry" || fields["PAYLOAD"] != payload {
+		t.Fatalf("unexpected entry: MESSAGE=%q, PAYLOAD of %d bytes", fields["MESSAGE"], len(fields["PAYLOAD"]))
+	}
+}
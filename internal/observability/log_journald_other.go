@@ -0,0 +1,12 @@
+//go:build !linux
+
+package observability
+
+import "net"
+
+// sendJournal writes msg as one datagram; journald only runs on Linux, so
+// the memfd fallback for large entries is not needed elsewhere
+func sendJournal(conn net.Conn, msg []byte) error {
+	_, err := conn.Write(msg)
+	return err
+}
@@ -0,0 +1,104 @@
+package observability_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+// parseJournal decodes a native protocol datagram into its fields
+func parseJournal(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	fields := make(map[string]string)
+	for len(data) > 0 {
+		line, rest, _ := bytes.Cut(data, []byte("\n"))
+		if name, value, ok := bytes.Cut(line, []byte("=")); ok {
+			fields[string(name)] = string(value)
+			data = rest
+			continue
+		}
+		// Binary form: NAME\n, uint64 length, value, \n
+		if len(rest) < 8 {
+			t.Fatalf("truncated field %q", line)
+		}
+		size := binary.LittleEndian.Uint64(rest[:8])
+		fields[string(line)] = string(rest[8 : 8+size])
+		data = rest[8+size+1:]
+	}
+	return fields
+}
+
+func TestJournaldSinkMapsFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	logger, err := observability.NewLogger("groningen", config.LoggingConfig{
+		Level:   "info",
+		Profile: "ENTERPRISE",
+		Sinks:   []config.LogSinkConfig{{Type: "journald", Address: path}},
+	})
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+
+	logger.Named("http").With(zap.String("request_id", "req-1")).Error("upstream failed\nretrying",
+		zap.Int("status", 502),
+		zap.Duration("elapsed", 1500*time.Millisecond),
+		zap.Any("upstream", map[string]any{"host": "db", "port": 5432}),
+		zap.Error(errors.New("connection refused")),
+		zap.String("message", "shadowed"))
+
+	buf := make([]byte, 65536)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := parseJournal(t, buf[:n])
+
+	for name, want := range map[string]string{
+		"MESSAGE":           "upstream failed\nretrying",
+		"PRIORITY":          "3",
+		"SYSLOG_IDENTIFIER": "groningen",
+		"LOGGER":            "http",
+		"SERVICE":           "groningen",
+		"REQUEST_ID":        "req-1",
+		"STATUS":            "502",
+		"ELAPSED":           "1.5s",
+		"UPSTREAM":          `{"host":"db","port":5432}`,
+		"ERROR":             "connection refused",
+		"FIELD_MESSAGE":     "shadowed",
+	} {
+		if fields[name] != want {
+			t.Errorf("%s = %q, want %q", name, fields[name], want)
+		}
+	}
+	if fields["STACKTRACE"] == "" {
+		t.Error("errors should carry STACKTRACE")
+	}
+	if fields["CORRELATION_ID"] == "" {
+		t.Error("camelCase correlationId should map to CORRELATION_ID")
+	}
+}
+
+func TestJournaldSinkRequiresSocket(t *testing.T) {
+	_, err := observability.NewLogger("svc", config.LoggingConfig{
+		Sinks: []config.LogSinkConfig{{Type: "journald", Address: filepath.Join(t.TempDir(), "missing.sock")}},
+	})
+	if err == nil {
+		t.Error("expected an error for a missing journald socket")
+	}
+}
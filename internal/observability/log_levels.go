@@ -0,0 +1,257 @@
+package observability
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fulmenhq/gofulmen/logging"
+	"go.uber.org/zap/zapcore"
+)
+
+// InheritLevel removes a component override so the component follows the
+// global level again
+const InheritLevel = "inherit"
+
+// LevelSetting is one level in a LevelStatus. ExpiresAt and RevertsTo are set
+// while a temporary override is active.
+type LevelSetting struct {
+	Level     string     `json:"level"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevertsTo string     `json:"reverts_to,omitempty"`
+}
+
+// LevelStatus is the global level plus per-component overrides, keyed by
+// logger name (see Logger.Named)
+type LevelStatus struct {
+	LevelSetting
+	Components map[string]LevelSetting `json:"components"`
+}
+
+// ParseLevel parses a log level name (trace, debug, info, warn, error;
+// case-insensitive), rejecting anything else
+func ParseLevel(level string) (logging.Severity, error) {
+	switch strings.ToLower(level) {
+	case "trace", "debug", "info", "warn", "warning", "error":
+		return logging.ParseSeverity(parseLogLevel(level)), nil
+	default:
+		return "", fmt.Errorf("unknown log level %q (want trace, debug, info, warn or error)", level)
+	}
+}
+
+// levelSetting is a level that may be temporary. revertTo is restored at
+// expiresAt; nil removes a component override instead.
+type levelSetting struct {
+	level     zapcore.Level
+	expiresAt time.Time
+	revertTo  *zapcore.Level
+	timer     *time.Timer
+}
+
+// persistent returns the level this setting falls back to once any
+// temporary override ends (nil: no component override)
+func (s *levelSetting) persistent() *zapcore.Level {
+	if s == nil {
+		return nil
+	}
+	if s.timer != nil {
+		return s.revertTo
+	}
+	level := s.level
+	return &level
+}
+
+// levelControl holds the global level and per-component overrides shared by
+// a logger and every logger derived from it
+type levelControl struct {
+	mu         sync.RWMutex
+	global     *levelSetting
+	components map[string]*levelSetting
+
+	// min is the lowest level any setting enables, checked on every call
+	// before the per-component lookup
+	min atomic.Int32
+}
+
+func newLevelControl(level zapcore.Level) *levelControl {
+	c := &levelControl{
+		global:     &levelSetting{level: level},
+		components: make(map[string]*levelSetting),
+	}
+	c.min.Store(int32(level))
+	return c
+}
+
+// enabled reports whether level passes for the logger name, using the most
+// specific component override ("http.client", then "http", then global)
+func (c *levelControl) enabled(name string, level zapcore.Level) bool {
+	if level < zapcore.Level(c.min.Load()) {
+		return false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for component := name; component != "" && len(c.components) > 0; component = parentComponent(component) {
+		if setting, ok := c.components[component]; ok {
+			return level >= setting.level
+		}
+	}
+	return level >= c.global.level
+}
+
+func parentComponent(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		return name[:i]
+	}
+	return ""
+}
+
+// set changes the level of component ("" for global). With ttl > 0 the
+// change is temporary and reverts to the previous persistent level.
+func (c *levelControl) set(component string, level zapcore.Level, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	current := c.setting(component)
+	if current != nil && current.timer != nil {
+		current.timer.Stop()
+	}
+
+	next := &levelSetting{level: level}
+	if ttl > 0 {
+		next.expiresAt = time.Now().Add(ttl)
+		next.revertTo = current.persistent()
+		next.timer = time.AfterFunc(ttl, func() { c.expire(component, next) })
+	}
+	c.store(component, next)
+}
+
+// setPersistent changes the level a temporary override reverts to, or the
+// level itself when no override is active
+func (c *levelControl) setPersistent(component string, level zapcore.Level) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if current := c.setting(component); current != nil && current.timer != nil {
+		current.revertTo = &level
+		return
+	}
+	c.store(component, &levelSetting{level: level})
+}
+
+// reset removes a component override
+func (c *levelControl) reset(component string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if current := c.components[component]; current != nil && current.timer != nil {
+		current.timer.Stop()
+	}
+	delete(c.components, component)
+	c.updateMin()
+}
+
+// expire ends a temporary override unless it has been replaced since
+func (c *levelControl) expire(component string, setting *levelSetting) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.setting(component) != setting {
+		return
+	}
+	if setting.revertTo == nil {
+		delete(c.components, component)
+		c.updateMin()
+		return
+	}
+	c.store(component, &levelSetting{level: *setting.revertTo})
+}
+
+func (c *levelControl) setting(component string) *levelSetting {
+	if component == "" {
+		return c.global
+	}
+	return c.components[component]
+}
+
+// store replaces a setting; c.mu must be held
+func (c *levelControl) store(component string, setting *levelSetting) {
+	if component == "" {
+		c.global = setting
+	} else {
+		c.components[component] = setting
+	}
+	c.updateMin()
+}
+
+func (c *levelControl) updateMin() {
+	lowest := c.global.level
+	for _, setting := range c.components {
+		lowest = min(lowest, setting.level)
+	}
+	c.min.Store(int32(lowest))
+}
+
+func (c *levelControl) globalLevel() zapcore.Level {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.global.level
+}
+
+func (c *levelControl) status() LevelStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	status := LevelStatus{
+		LevelSetting: c.global.describe(),
+		Components:   make(map[string]LevelSetting, len(c.components)),
+	}
+	names := make([]string, 0, len(c.components))
+	for name := range c.components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		status.Components[name] = c.components[name].describe()
+	}
+	return status
+}
+
+func (s *levelSetting) describe() LevelSetting {
+	setting := LevelSetting{Level: severityName(s.level)}
+	if s.timer != nil {
+		expiresAt := s.expiresAt.UTC()
+		setting.ExpiresAt = &expiresAt
+		setting.RevertsTo = InheritLevel
+		if s.revertTo != nil {
+			setting.RevertsTo = severityName(*s.revertTo)
+		}
+	}
+	return setting
+}
+
+// levelCore applies the level control ahead of the sinks. Enabled only
+// answers whether any setting could log the level; Check makes the
+// per-component decision with the entry's logger name.
+type levelCore struct {
+	zapcore.Core
+	levels *levelControl
+}
+
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return level >= zapcore.Level(c.levels.min.Load()) && c.Core.Enabled(level)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), levels: c.levels}
+}
+
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.levels.enabled(entry.LoggerName, entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
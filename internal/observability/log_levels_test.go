@@ -0,0 +1,89 @@
+package observability_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fulmenhq/gofulmen/logging"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+func TestComponentLevelOverrides(t *testing.T) {
+	logger, buf := newTestLogger(t, config.LoggingConfig{Level: "info"})
+
+	logger.SetComponentLevel("http", logging.DEBUG, 0)
+	logger.Named("http").Named("client").Debug("child inherits http")
+	logger.Named("health").Debug("health follows global")
+	logger.Debug("global stays at info")
+
+	lines := decodeLines(t, buf)
+	if len(lines) != 1 || lines[0]["message"] != "child inherits http" {
+		t.Fatalf("unexpected output: %s", buf.String())
+	}
+
+	buf.Reset()
+	logger.SetComponentLevel("http", logging.ERROR, 0)
+	logger.Named("http").Warn("quiet component")
+	logger.Warn("global still warns")
+	if got := buf.String(); strings.Contains(got, "quiet component") || !strings.Contains(got, "global still warns") {
+		t.Fatalf("component override should raise only http: %s", got)
+	}
+
+	logger.ResetComponentLevel("http")
+	if status := logger.Levels(); len(status.Components) != 0 || status.Level != "INFO" {
+		t.Fatalf("unexpected status after reset: %+v", status)
+	}
+}
+
+func TestTemporaryLevelReverts(t *testing.T) {
+	logger, _ := newTestLogger(t, config.LoggingConfig{Level: "info"})
+
+	logger.SetComponentLevel("", logging.DEBUG, 50*time.Millisecond)
+	logger.SetComponentLevel("health", logging.DEBUG, 50*time.Millisecond)
+
+	status := logger.Levels()
+	if status.Level != "DEBUG" || status.ExpiresAt == nil || status.RevertsTo != "INFO" {
+		t.Fatalf("unexpected global status: %+v", status.LevelSetting)
+	}
+	if got := status.Components["health"]; got.RevertsTo != observability.InheritLevel {
+		t.Fatalf("unexpected component status: %+v", got)
+	}
+
+	// A config reload during the override changes what it reverts to
+	logger.SetConfiguredLevel(logging.WARN)
+	if logger.GetLevel() != logging.DEBUG {
+		t.Fatalf("reload should not end the override, level is %s", logger.GetLevel())
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for logger.GetLevel() != logging.WARN || len(logger.Levels().Components) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("override did not revert: %+v", logger.Levels())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSetLevelEndsTemporaryOverride(t *testing.T) {
+	logger, _ := newTestLogger(t, config.LoggingConfig{Level: "info"})
+
+	logger.SetComponentLevel("", logging.DEBUG, 20*time.Millisecond)
+	logger.SetLevel(logging.ERROR)
+	time.Sleep(50 * time.Millisecond)
+
+	if status := logger.Levels(); status.Level != "ERROR" || status.ExpiresAt != nil {
+		t.Fatalf("SetLevel should be permanent: %+v", status.LevelSetting)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	if severity, err := observability.ParseLevel("Warn"); err != nil || severity != logging.WARN {
+		t.Fatalf("ParseLevel(Warn) = %s, %v", severity, err)
+	}
+	if _, err := observability.ParseLevel("verbose"); err == nil {
+		t.Fatal("expected an error for an unknown level")
+	}
+}
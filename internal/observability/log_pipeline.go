@@ -0,0 +1,142 @@
+package observability
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/fulmenhq/gofulmen/logging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// envelopeKeys are fields gofulmen lifts out of the event context into the
+// envelope; middleware does not rewrite them, so they pass through unchanged
+var envelopeKeys = map[string]bool{
+	"traceId": true, "trace_id": true,
+	"spanId": true, "span_id": true,
+	"parentSpanId": true, "parent_span_id": true,
+	"correlationId": true, "correlation_id": true,
+	"requestId": true, "request_id": true,
+	"contextId": true, "context_id": true,
+	"userId": true, "user_id": true,
+	"operation":  true,
+	"durationMs": true, "duration_ms": true,
+	"eventId": true, "event_id": true,
+	"tags":  true,
+	"error": true,
+}
+
+// pipelineCore runs gofulmen logging middleware before the sinks. Fields
+// added with With are held here instead of being encoded by the sinks, so
+// middleware such as redaction sees every field of an entry.
+type pipelineCore struct {
+	next     zapcore.Core
+	pipeline *logging.MiddlewarePipeline
+	envelope *logging.LoggerConfig
+	fields   []zapcore.Field
+}
+
+func (c *pipelineCore) Enabled(level zapcore.Level) bool {
+	return c.next.Enabled(level)
+}
+
+func (c *pipelineCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	return &clone
+}
+
+func (c *pipelineCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *pipelineCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	all := append(c.fields[:len(c.fields):len(c.fields)], fields...)
+
+	encoded := zapcore.NewMapObjectEncoder()
+	for i := range all {
+		all[i].AddTo(encoded)
+	}
+
+	event := logging.NewLogEvent(entry, all, c.envelope)
+	if !hasAnyKey(encoded.Fields, "correlationId", "correlation_id") {
+		// NewLogEvent always invents one; leave that to the correlation middleware
+		event.CorrelationID = ""
+	}
+	event = c.pipeline.Process(event)
+	if event == nil {
+		return nil // dropped, e.g. by throttling
+	}
+
+	// Check again so each sink applies its own level
+	entry.Message = event.Message
+	if checked := c.next.Check(entry, nil); checked != nil {
+		checked.Write(applyEvent(event, all, encoded.Fields)...)
+	}
+	return nil
+}
+
+func (c *pipelineCore) Sync() error {
+	return c.next.Sync()
+}
+
+// applyEvent turns a processed event back into fields. Untouched fields keep
+// their original encoding and order; fields whose context value changed are
+// replaced, removed ones are dropped and new ones are appended.
+func applyEvent(event *logging.LogEvent, fields []zapcore.Field, encoded map[string]any) []zapcore.Field {
+	out := make([]zapcore.Field, 0, len(fields)+1)
+	seen := make(map[string]bool, len(fields))
+
+	// Walk backwards so the last field with a key wins, as in the encoders
+	for i := len(fields) - 1; i >= 0; i-- {
+		field := fields[i]
+		if seen[field.Key] {
+			continue
+		}
+		seen[field.Key] = true
+
+		original, wasEncoded := encoded[field.Key]
+		value, inContext := event.Context[field.Key]
+		switch {
+		case !wasEncoded, envelopeKeys[field.Key]:
+			out = append(out, field)
+		case !inContext:
+			// removed by middleware
+		case reflect.DeepEqual(value, original):
+			out = append(out, field)
+		default:
+			out = append(out, zap.Any(field.Key, value))
+		}
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+
+	var added []string
+	for key := range event.Context {
+		if !seen[key] {
+			added = append(added, key)
+		}
+	}
+	sort.Strings(added)
+	for _, key := range added {
+		out = append(out, zap.Any(key, event.Context[key]))
+	}
+
+	if event.CorrelationID != "" && !seen["correlationId"] && !seen["correlation_id"] {
+		out = append(out, zap.String("correlationId", event.CorrelationID))
+	}
+	return out
+}
+
+func hasAnyKey(fields map[string]any, keys ...string) bool {
+	for _, key := range keys {
+		if _, ok := fields[key]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,299 @@
+package observability
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// syslogWriteTimeout bounds a write to a remote syslog server, so a stalled
+// collector cannot block logging indefinitely
+const syslogWriteTimeout = 5 * time.Second
+
+const (
+	// syslogRetryDelay is the wait before redialing a collector that failed;
+	// it doubles per failed dial
+	syslogRetryDelay = time.Second
+
+	// syslogMaxBackoff caps the wait between dials
+	syslogMaxBackoff = time.Minute
+)
+
+// localSyslogSockets are tried in order when a syslog sink has no address
+var localSyslogSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// syslogFacilities maps facility names to RFC 5424 facility codes
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5,
+	"lpr": 6, "news": 7, "uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// syslogFacility resolves a facility name, defaulting to daemon
+func syslogFacility(name string) (int, error) {
+	if name == "" {
+		return syslogFacilities["daemon"], nil
+	}
+	facility, ok := syslogFacilities[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown syslog facility %q (want daemon, user, local0-local7, ...)", name)
+	}
+	return facility, nil
+}
+
+// syslogSeverity maps zap levels to RFC 5424 severities, also used as the
+// journald PRIORITY
+func syslogSeverity(l zapcore.Level) int {
+	switch {
+	case l <= zapcore.DebugLevel:
+		return 7 // debug
+	case l == zapcore.InfoLevel:
+		return 6 // informational
+	case l == zapcore.WarnLevel:
+		return 4 // warning
+	case l == zapcore.ErrorLevel:
+		return 3 // error
+	default:
+		return 2 // critical
+	}
+}
+
+// syslogFraming is how messages are delimited on the connection
+type syslogFraming int
+
+const (
+	framingDatagram syslogFraming = iota // one message per datagram
+	framingOctet                         // RFC 6587 octet counting (TCP)
+	framingNewline                       // newline-terminated (unix stream sockets)
+)
+
+var syslogBufferPool = buffer.NewPool()
+
+// syslogEncoder prefixes each entry, encoded by the sink format, with an
+// RFC 5424 header: <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID -
+type syslogEncoder struct {
+	zapcore.Encoder
+	facility int
+	hostname string
+	appName  string
+	procID   string
+}
+
+func newSyslogEncoder(inner zapcore.Encoder, facility int, appName string) *syslogEncoder {
+	hostname, _ := os.Hostname()
+	return &syslogEncoder{
+		Encoder:  inner,
+		facility: facility,
+		hostname: syslogHeaderField(hostname, 255),
+		appName:  syslogHeaderField(appName, 48),
+		procID:   strconv.Itoa(os.Getpid()),
+	}
+}
+
+func (e *syslogEncoder) Clone() zapcore.Encoder {
+	clone := *e
+	clone.Encoder = e.Encoder.Clone()
+	return &clone
+}
+
+func (e *syslogEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	body, err := e.Encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Free()
+
+	buf := syslogBufferPool.Get()
+	buf.AppendByte('<')
+	buf.AppendInt(int64(e.facility*8 + syslogSeverity(entry.Level)))
+	buf.AppendString(">1 ")
+	buf.AppendTime(entry.Time, "2006-01-02T15:04:05.000000Z07:00")
+	buf.AppendByte(' ')
+	buf.AppendString(e.hostname)
+	buf.AppendByte(' ')
+	buf.AppendString(e.appName)
+	buf.AppendByte(' ')
+	buf.AppendString(e.procID)
+	buf.AppendByte(' ')
+	buf.AppendString(syslogHeaderField(entry.LoggerName, 32))
+	buf.AppendString(" - ")
+	_, _ = buf.Write(bytes.TrimRight(body.Bytes(), "\n"))
+	return buf, nil
+}
+
+// syslogHeaderField makes s a valid header field: printable ASCII without
+// spaces, at most limit characters, "-" when empty
+func syslogHeaderField(s string, limit int) string {
+	if s == "" {
+		return "-"
+	}
+	b := []byte(s)
+	for i, c := range b {
+		if c < 33 || c > 126 {
+			b[i] = '_'
+		}
+	}
+	if len(b) > limit {
+		b = b[:limit]
+	}
+	return string(b)
+}
+
+// syslogWriter sends each write as one syslog message, reconnecting once
+// when a write fails (collector restart, rotated socket). After a failed
+// dial, entries are dropped and counted until the backoff passes, so an
+// unreachable collector does not stall every log call.
+type syslogWriter struct {
+	mu      sync.Mutex
+	network string
+	address string
+	framing syslogFraming
+	conn    net.Conn
+
+	backoff time.Duration
+	retryAt time.Time
+	dropped int
+}
+
+// newSyslogWriter parses address (unix:///dev/log, udp://host:514,
+// tcp://host:601 or a socket path) and connects, so a bad address fails
+// startup. An empty address uses the local syslog socket.
+func newSyslogWriter(address string) (*syslogWriter, error) {
+	if address == "" {
+		for _, path := range localSyslogSockets {
+			if _, err := os.Stat(path); err == nil {
+				address = path
+				break
+			}
+		}
+		if address == "" {
+			return nil, fmt.Errorf("syslog sink: no local syslog socket (%s); set address", strings.Join(localSyslogSockets, ", "))
+		}
+	}
+
+	w := &syslogWriter{}
+	if strings.HasPrefix(address, "/") {
+		w.network, w.address = "unix", address
+	} else {
+		u, err := url.Parse(address)
+		if err != nil {
+			return nil, fmt.Errorf("syslog sink address %q: %w", address, err)
+		}
+		switch u.Scheme {
+		case "unix":
+			w.network, w.address = "unix", u.Path
+		case "udp", "tcp":
+			if u.Port() == "" {
+				return nil, fmt.Errorf("syslog sink address %q: missing port", address)
+			}
+			w.network, w.address = u.Scheme, u.Host
+		default:
+			return nil, fmt.Errorf("syslog sink address %q: scheme must be unix, udp or tcp", address)
+		}
+	}
+
+	if err := w.connect(); err != nil {
+		return nil, fmt.Errorf("syslog sink %s: %w", address, err)
+	}
+	return w, nil
+}
+
+// connect dials the collector. Unix sockets are datagram sockets on most
+// systems; stream sockets get newline framing.
+func (w *syslogWriter) connect() error {
+	switch w.network {
+	case "unix":
+		conn, err := net.Dial("unixgram", w.address)
+		if err == nil {
+			w.conn, w.framing = conn, framingDatagram
+			return nil
+		}
+		if conn, err = net.Dial("unix", w.address); err != nil {
+			return err
+		}
+		w.conn, w.framing = conn, framingNewline
+	case "udp":
+		conn, err := net.Dial("udp", w.address)
+		if err != nil {
+			return err
+		}
+		w.conn, w.framing = conn, framingDatagram
+	default:
+		conn, err := net.DialTimeout("tcp", w.address, syslogWriteTimeout)
+		if err != nil {
+			return err
+		}
+		w.conn, w.framing = conn, framingOctet
+	}
+	return nil
+}
+
+func (w *syslogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn != nil {
+		if err := w.send(p); err == nil {
+			return len(p), nil
+		}
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+
+	if time.Now().Before(w.retryAt) {
+		w.dropped++
+		return len(p), nil
+	}
+	if err := w.connect(); err != nil {
+		w.backoff = min(max(2*w.backoff, syslogRetryDelay), syslogMaxBackoff)
+		w.retryAt = time.Now().Add(w.backoff)
+		w.dropped++
+		w.report("%v; dropping entries, retrying in %s", err, w.backoff)
+		return len(p), nil
+	}
+	if w.dropped > 0 {
+		w.report("reconnected, dropped %d entries", w.dropped)
+	}
+	w.backoff, w.retryAt, w.dropped = 0, time.Time{}, 0
+	if err := w.send(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// report writes a delivery problem to stderr, since the sink cannot log
+// through the logger it belongs to
+func (w *syslogWriter) report(format string, args ...any) {
+	_, _ = fmt.Fprintf(shipErrorOutput, "syslog sink %s://%s: %s\n", w.network, w.address, fmt.Sprintf(format, args...))
+}
+
+func (w *syslogWriter) send(msg []byte) error {
+	if w.network != "unix" {
+		_ = w.conn.SetWriteDeadline(time.Now().Add(syslogWriteTimeout))
+	}
+
+	var err error
+	switch w.framing {
+	case framingOctet:
+		_, err = w.conn.Write(append([]byte(strconv.Itoa(len(msg))+" "), msg...))
+	case framingNewline:
+		_, err = w.conn.Write(append(msg[:len(msg):len(msg)], '\n'))
+	default:
+		_, err = w.conn.Write(msg)
+	}
+	return err
+}
+
+func (w *syslogWriter) Sync() error {
+	return nil
+}
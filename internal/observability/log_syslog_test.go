@@ -0,0 +1,216 @@
+package observability_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+// rfc5424 matches the header the syslog sink writes and captures PRI, APP-NAME,
+// MSGID and the message body
+var rfc5424 = regexp.MustCompile(`^<(\d+)>1 \d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{6}(?:Z|[+-]\d{2}:\d{2}) \S+ (\S+) \d+ (\S+) - (.*)$`)
+
+func newSyslogLogger(t *testing.T, sink config.LogSinkConfig) *observability.Logger {
+	t.Helper()
+	sink.Type = "syslog"
+	logger, err := observability.NewLogger("groningen", config.LoggingConfig{
+		Level:   "debug",
+		Profile: "ENTERPRISE",
+		Sinks:   []config.LogSinkConfig{sink},
+	})
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+	return logger
+}
+
+// parseSyslog checks the header and returns PRI, MSGID and the JSON body
+func parseSyslog(t *testing.T, msg string) (int, string, map[string]any) {
+	t.Helper()
+	m := rfc5424.FindStringSubmatch(msg)
+	if m == nil {
+		t.Fatalf("not an RFC 5424 message: %q", msg)
+	}
+	if m[2] != "groningen" {
+		t.Errorf("APP-NAME = %q, want groningen", m[2])
+	}
+	pri, _ := strconv.Atoi(m[1])
+	var body map[string]any
+	if err := json.Unmarshal([]byte(m[4]), &body); err != nil {
+		t.Fatalf("body is not JSON: %q", m[4])
+	}
+	return pri, m[3], body
+}
+
+func TestSyslogSinkOverUnixDatagram(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	logger := newSyslogLogger(t, config.LogSinkConfig{Address: "unix://" + path, Facility: "local3"})
+	logger.Named("http").Warn("slow upstream", zap.Int("attempt", 2))
+
+	buf := make([]byte, 4096)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pri, msgID, body := parseSyslog(t, string(buf[:n]))
+	if want := 19*8 + 4; pri != want { // local3, warning
+		t.Errorf("PRI = %d, want %d", pri, want)
+	}
+	if msgID != "http" {
+		t.Errorf("MSGID = %q, want the logger name", msgID)
+	}
+	if body["message"] != "slow upstream" || body["attempt"] != float64(2) {
+		t.Errorf("body = %v", body)
+	}
+}
+
+func TestSyslogSinkOverUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	logger := newSyslogLogger(t, config.LogSinkConfig{Address: "udp://" + conn.LocalAddr().String()})
+	logger.Debug("cache miss")
+
+	buf := make([]byte, 4096)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pri, msgID, body := parseSyslog(t, string(buf[:n]))
+	if want := 3*8 + 7; pri != want { // daemon, debug
+		t.Errorf("PRI = %d, want %d", pri, want)
+	}
+	if msgID != "-" || body["message"] != "cache miss" {
+		t.Errorf("MSGID = %q, body = %v", msgID, body)
+	}
+}
+
+func TestSyslogSinkOverTCPUsesOctetCounting(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		reader := bufio.NewReader(conn)
+		var msgs []string
+		for len(msgs) < 2 {
+			length, err := reader.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(length))
+			msg := make([]byte, n)
+			if _, err := reader.Read(msg); err != nil {
+				return
+			}
+			msgs = append(msgs, string(msg))
+		}
+		received <- msgs
+	}()
+
+	logger := newSyslogLogger(t, config.LogSinkConfig{Address: "tcp://" + listener.Addr().String()})
+	logger.Info("first")
+	logger.Error("second\nwith a newline")
+
+	select {
+	case msgs := <-received:
+		_, _, first := parseSyslog(t, msgs[0])
+		pri, _, second := parseSyslog(t, msgs[1])
+		if first["message"] != "first" || second["message"] != "second\nwith a newline" {
+			t.Errorf("messages = %v, %v", first, second)
+		}
+		if want := 3*8 + 3; pri != want {
+			t.Errorf("PRI = %d, want %d", pri, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("syslog server received nothing")
+	}
+}
+
+func TestSyslogSinkBacksOffWhileCollectorIsDown(t *testing.T) {
+	var reports bytes.Buffer
+	t.Cleanup(observability.SetShipErrorOutput(&reports))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			accepted <- conn
+		}
+	}()
+
+	logger := newSyslogLogger(t, config.LogSinkConfig{Address: "tcp://" + listener.Addr().String()})
+	_ = listener.Close()
+	_ = (<-accepted).Close()
+
+	// The first writes notice the closed connection and fail to redial
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(reports.String(), "dropping entries") {
+		if time.Now().After(deadline) {
+			t.Fatalf("no dial failure reported: %q", reports.String())
+		}
+		logger.Info("probe")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	start := time.Now()
+	for i := 0; i < 200; i++ {
+		logger.Info("during outage")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("200 writes took %s while the collector was down", elapsed)
+	}
+	if n := strings.Count(reports.String(), "dropping entries"); n != 1 {
+		t.Errorf("dial failures reported %d times, want 1 within the backoff: %q", n, reports.String())
+	}
+}
+
+func TestSyslogSinkRejectsInvalidConfig(t *testing.T) {
+	for name, sink := range map[string]config.LogSinkConfig{
+		"facility": {Type: "syslog", Address: "udp://127.0.0.1:514", Facility: "local9"},
+		"scheme":   {Type: "syslog", Address: "http://127.0.0.1:514"},
+		"port":     {Type: "syslog", Address: "udp://127.0.0.1"},
+		"socket":   {Type: "syslog", Address: filepath.Join(t.TempDir(), "missing.sock")},
+	} {
+		_, err := observability.NewLogger("svc", config.LoggingConfig{Sinks: []config.LogSinkConfig{sink}})
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
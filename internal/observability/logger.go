@@ -3,17 +3,21 @@ package observability
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/fulmenhq/gofulmen/foundry"
 	"github.com/fulmenhq/gofulmen/logging"
+	"go.uber.org/zap"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
 )
 
 var (
 	// CLILogger is used for CLI commands (SIMPLE profile)
 	CLILogger *logging.Logger
 
-	// ServerLogger is used for HTTP server (STRUCTURED profile)
-	ServerLogger *logging.Logger
+	// ServerLogger is used for HTTP server (profile from logging config)
+	ServerLogger *Logger
 )
 
 // InitCLILogger initializes the CLI logger with SIMPLE profile
@@ -32,56 +36,28 @@ func InitCLILogger(serviceName string, verbose bool) {
 	CLILogger = logger
 }
 
-// InitServerLogger initializes the server logger with STRUCTURED profile
-// Optional namespace parameter for telemetry integration
-func InitServerLogger(serviceName string, logLevel string, namespace ...string) {
-	level := parseLogLevel(logLevel)
-
-	// Build static fields with optional namespace
-	staticFields := make(map[string]any)
+// InitServerLogger builds the server logger from logging config. The profile
+// picks the shape: SIMPLE is colorized console text, STRUCTURED is one JSON
+// sink with correlation IDs, ENTERPRISE adds multiple sinks, middleware and
+// throttling. Optional namespace parameter for telemetry integration.
+func InitServerLogger(serviceName string, cfg config.LoggingConfig, namespace ...string) error {
+	var staticFields []zap.Field
 	if len(namespace) > 0 && namespace[0] != "" {
-		staticFields["namespace"] = namespace[0]
-	}
-
-	config := &logging.LoggerConfig{
-		Profile:      logging.ProfileStructured,
-		DefaultLevel: level,
-		Service:      serviceName,
-		Environment:  "production",
-		StaticFields: staticFields,
-		Middleware: []logging.MiddlewareConfig{
-			{
-				Name:    "correlation",
-				Enabled: true,
-				Order:   100,
-				Config:  make(map[string]any),
-			},
-		},
-		Sinks: []logging.SinkConfig{
-			{
-				Type:   "console",
-				Format: "json",
-				Console: &logging.ConsoleSinkConfig{
-					Stream:   "stderr",
-					Colorize: false,
-				},
-			},
-		},
-		EnableCaller:     true,
-		EnableStacktrace: true,
+		staticFields = append(staticFields, zap.String("namespace", namespace[0]))
 	}
 
-	logger, err := logging.New(config)
+	logger, err := NewLogger(serviceName, cfg, staticFields...)
 	if err != nil {
-		exitWithCodeStderr(foundry.ExitConfigInvalid, "Failed to initialize server logger", err)
+		return err
 	}
 
 	ServerLogger = logger
+	return nil
 }
 
 // parseLogLevel converts string log level to logging severity string
 func parseLogLevel(levelStr string) string {
-	switch levelStr {
+	switch strings.ToLower(levelStr) {
 	case "trace":
 		return "TRACE"
 	case "debug":
@@ -1,64 +1,88 @@
 package observability
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"net"
-	"strconv"
+	"net/http"
+	"sync"
+	"time"
 
 	"github.com/fulmenhq/gofulmen/telemetry"
-	"github.com/fulmenhq/gofulmen/telemetry/exporters"
 )
 
 var (
 	// TelemetrySystem is the global telemetry system
 	TelemetrySystem *telemetry.System
 
-	// PrometheusExporter is the prometheus metrics exporter
-	PrometheusExporter *exporters.PrometheusExporter
+	// MetricsRegistry stores the aggregated metrics rendered by /metrics and
+	// sent by push exporters
+	MetricsRegistry *Registry
 
-	// metricsPort stores the port the Prometheus exporter is listening on
+	// metricsPort stores the port the dedicated metrics listener is bound to
+	// (0 when metrics are only served on the main router)
 	metricsPort int
+
+	// metricsServer is the dedicated listener on metrics.port
+	metricsServer *http.Server
+
+	// metricsNamespace is the prefix applied to rendered metric names
+	metricsNamespace string
+
+	// metricsService is the service.name reported by push exporters
+	metricsService string
+
+	// metricsStart is the start time reported for cumulative push series
+	metricsStart time.Time
+
+	// metricsDisabled is set when metrics are turned off by configuration
+	metricsDisabled bool
+
+	// labelLimiter caps per-label cardinality between TelemetrySystem and the registry
+	labelLimiter   *CardinalityLimiter
+	maxLabelValues = DefaultMaxLabelValues
+
+	// collectors refresh point-in-time metrics right before they are rendered
+	collectorsMu sync.Mutex
+	collectors   []func()
 )
 
-// InitMetrics initializes the telemetry system with Prometheus exporter.
-// The exporter listens on the provided port (use 0 for random assignment).
+// InitMetrics initializes the telemetry system with a fresh metrics registry.
+// When port is greater than zero /metrics is also served on its own
+// listener; use 0 to serve metrics only in-process on the main HTTP router.
+// Both render through WriteMetrics, so they show the same output.
 // Optional namespace parameter for telemetry integration.
 func InitMetrics(serviceName string, port int, namespace ...string) error {
-	requestedPort := port
-	if requestedPort < 0 {
-		requestedPort = 0
-	}
-	// Store requested port as default in case discovery fails
-	metricsPort = requestedPort
+	stopMetricsListener()
+	metricsPort = 0
+	metricsDisabled = false
+	metricsService = serviceName
+	metricsStart = time.Now()
+	discardPushLoops()
+	resetCollectors()
+	resetExemplars()
 
 	// Use namespace if provided, otherwise use service name
-	metricNamespace := serviceName
+	metricsNamespace = serviceName
 	if len(namespace) > 0 && namespace[0] != "" {
-		metricNamespace = namespace[0]
+		metricsNamespace = namespace[0]
 	}
 
-	endpoint := fmt.Sprintf(":%d", requestedPort)
+	MetricsRegistry = NewRegistry()
 
-	// Create Prometheus exporter with namespace
-	PrometheusExporter = exporters.NewPrometheusExporter(metricNamespace, endpoint)
-
-	// Start Prometheus HTTP server
-	if err := PrometheusExporter.Start(); err != nil {
-		return err
+	if port > 0 {
+		if err := startMetricsListener(fmt.Sprintf(":%d", port)); err != nil {
+			return err
+		}
 	}
 
-	// Update metricsPort with the actual port the exporter bound to
-	if actualPort, err := resolvePort(PrometheusExporter.GetAddr()); err == nil {
-		metricsPort = actualPort
-	} else if requestedPort == 0 {
-		// Fall back to default port if we requested :0 and could not determine actual port
-		metricsPort = 9090
-	}
-
-	// Create telemetry system with Prometheus exporter
+	// Create telemetry system with the registry behind the cardinality guard
+	labelLimiter = NewCardinalityLimiter(MetricsRegistry, maxLabelValues)
 	config := &telemetry.Config{
 		Enabled: true,
-		Emitter: PrometheusExporter,
+		Emitter: labelLimiter,
 	}
 
 	sys, err := telemetry.NewSystem(config)
@@ -75,19 +99,121 @@ func InitMetrics(serviceName string, port int, namespace ...string) error {
 	return nil
 }
 
-// GetMetricsPort returns the port the Prometheus exporter is listening on
+// SetMaxLabelValues sets how many distinct values each metric label may take
+// before further values collapse to OverflowLabelValue. 0 (an unset config
+// key) uses DefaultMaxLabelValues; a negative value disables the guard.
+func SetMaxLabelValues(n int) {
+	if n == 0 {
+		n = DefaultMaxLabelValues
+	}
+	maxLabelValues = n
+	if labelLimiter != nil {
+		labelLimiter.SetMaxValues(n)
+	}
+}
+
+// DisableMetrics switches telemetry into no-op mode. The telemetry system and
+// registry are left nil, so every metrics helper returns without doing work.
+func DisableMetrics() {
+	stopMetricsListener()
+	MetricsRegistry = nil
+	TelemetrySystem = nil
+	metricsPort = 0
+	metricsDisabled = true
+	discardPushLoops()
+	resetCollectors()
+	resetExemplars()
+}
+
+// MetricsEnabled reports whether metrics were turned off with DisableMetrics
+func MetricsEnabled() bool {
+	return !metricsDisabled
+}
+
+// GetMetricsPort returns the port the dedicated metrics listener is bound to,
+// or 0 when it is disabled
 func GetMetricsPort() int {
 	return metricsPort
 }
 
-func resolvePort(addr string) (int, error) {
-	_, portStr, err := net.SplitHostPort(addr)
-	if err != nil {
-		return 0, err
+// RegisterCollector adds a function that emits point-in-time metrics (runtime
+// stats, uptime) through TelemetrySystem. Collectors run on every scrape.
+// Registered collectors are cleared by InitMetrics and DisableMetrics.
+func RegisterCollector(collect func()) {
+	collectorsMu.Lock()
+	defer collectorsMu.Unlock()
+	collectors = append(collectors, collect)
+}
+
+// Collect runs every registered collector
+func Collect() {
+	collectorsMu.Lock()
+	defer collectorsMu.Unlock()
+	for _, collect := range collectors {
+		collect()
+	}
+}
+
+func resetCollectors() {
+	collectorsMu.Lock()
+	defer collectorsMu.Unlock()
+	collectors = nil
+}
+
+// WriteMetrics runs the registered collectors and renders the current metrics
+// registry in the requested exposition format
+func WriteMetrics(w io.Writer, format ExpositionFormat) error {
+	Collect()
+	if format == FormatOpenMetrics {
+		return WriteOpenMetrics(w, MetricsRegistry, metricsNamespace)
 	}
-	port, err := strconv.Atoi(portStr)
+	return WritePrometheus(w, MetricsRegistry, metricsNamespace)
+}
+
+// MetricsHTTPHandler serves WriteMetrics over HTTP, negotiating OpenMetrics
+// from the Accept header. It backs the dedicated metrics.port listener.
+func MetricsHTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if MetricsRegistry == nil {
+			http.Error(w, "metrics not initialized", http.StatusServiceUnavailable)
+			return
+		}
+
+		format := NegotiateFormat(r.Header.Get("Accept"))
+		var buf bytes.Buffer
+		if err := WriteMetrics(&buf, format); err != nil {
+			http.Error(w, "unable to render metrics", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", format.ContentType())
+		w.Header().Add("Vary", "Accept")
+		_, _ = buf.WriteTo(w)
+	})
+}
+
+// startMetricsListener serves /metrics on addr in the background
+func startMetricsListener(addr string) error {
+	listener, err := net.Listen("tcp", addr)
 	if err != nil {
-		return 0, err
+		return fmt.Errorf("failed to start metrics listener: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", MetricsHTTPHandler())
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() { _ = srv.Serve(listener) }()
+
+	metricsServer = srv
+	metricsPort = listener.Addr().(*net.TCPAddr).Port
+	return nil
+}
+
+func stopMetricsListener() {
+	if metricsServer == nil {
+		return
 	}
-	return port, nil
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_ = metricsServer.Shutdown(ctx)
+	metricsServer = nil
 }
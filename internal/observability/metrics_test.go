@@ -0,0 +1,90 @@
+package observability_test
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+func TestMetricsListenerRendersCollectedMetrics(t *testing.T) {
+	probe, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("loopback listener unavailable: %v", err)
+	}
+	port := probe.Addr().(*net.TCPAddr).Port
+	_ = probe.Close()
+
+	if err := observability.InitMetrics("test", port, "test"); err != nil {
+		t.Fatalf("failed to init metrics: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = observability.InitMetrics("test", 0, "test") // stops the listener
+		observability.MetricsRegistry = nil
+		observability.TelemetrySystem = nil
+	})
+	if observability.GetMetricsPort() != port {
+		t.Fatalf("metrics port = %d, want %d", observability.GetMetricsPort(), port)
+	}
+
+	observability.RegisterCollector(func() {
+		_ = observability.TelemetrySystem.Gauge("collected_at_scrape", 1, nil)
+	})
+
+	resp, err := http.Get("http://" + probe.Addr().String() + "/metrics")
+	if err != nil {
+		t.Fatalf("scrape failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.Header.Get("Content-Type") != observability.PrometheusContentType {
+		t.Errorf("Content-Type = %q", resp.Header.Get("Content-Type"))
+	}
+	if !strings.Contains(string(body), "# TYPE test_collected_at_scrape gauge\ntest_collected_at_scrape 1\n") {
+		t.Errorf("collectors should run on the dedicated listener too, got:\n%s", body)
+	}
+}
+
+func TestRegistryAggregatesAsEventsArrive(t *testing.T) {
+	registry := observability.NewRegistry()
+	for i := 0; i < 1000; i++ {
+		_ = registry.Counter("requests_total", 1, map[string]string{"status": "200"})
+		_ = registry.Histogram("request_duration_ms", 20*time.Millisecond, nil)
+	}
+	_ = registry.Histogram("gc_pause_seconds", 500*time.Millisecond, nil)
+	if err := registry.Gauge("requests_total", 1, nil); err == nil {
+		t.Error("reusing a counter name for a gauge should be rejected")
+	}
+
+	families, err := observability.Snapshot(registry, "")
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	byName := map[string]observability.MetricFamily{}
+	for _, family := range families {
+		byName[family.Name] = family
+	}
+
+	counter := byName["requests_total"]
+	if len(counter.Series) != 1 || counter.Series[0].Value != 1000 {
+		t.Errorf("counter = %+v, want one series at 1000", counter.Series)
+	}
+	// _ms histograms keep milliseconds, _seconds ones are converted
+	if ms := byName["request_duration_ms"].Series[0]; ms.Count != 1000 || ms.Sum != 20000 {
+		t.Errorf("request_duration_ms count=%d sum=%v, want 1000 and 20000ms", ms.Count, ms.Sum)
+	}
+	if s := byName["gc_pause_seconds"].Series[0]; s.Sum != 0.5 {
+		t.Errorf("gc_pause_seconds sum = %v, want 0.5", s.Sum)
+	}
+
+	// Snapshots are copies; later events do not change them
+	_ = registry.Counter("requests_total", 1, map[string]string{"status": "200"})
+	if counter.Series[0].Value != 1000 {
+		t.Error("snapshot changed after a later event")
+	}
+}
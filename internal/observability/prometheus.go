@@ -0,0 +1,196 @@
+package observability
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fulmenhq/gofulmen/telemetry"
+)
+
+const (
+	// PrometheusContentType is the exposition format written by WritePrometheus
+	PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+	// OpenMetricsContentType is the exposition format written by WriteOpenMetrics
+	OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+// ExpositionFormat selects the text format used to render metrics
+type ExpositionFormat int
+
+const (
+	// FormatPrometheus is the Prometheus 0.0.4 text format
+	FormatPrometheus ExpositionFormat = iota
+
+	// FormatOpenMetrics is OpenMetrics 1.0, which adds exemplars
+	FormatOpenMetrics
+)
+
+// ContentType returns the Content-Type header value for the format
+func (f ExpositionFormat) ContentType() string {
+	if f == FormatOpenMetrics {
+		return OpenMetricsContentType
+	}
+	return PrometheusContentType
+}
+
+// NegotiateFormat picks OpenMetrics when the Accept header asks for it and
+// falls back to the Prometheus text format otherwise
+func NegotiateFormat(accept string) ExpositionFormat {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		if strings.TrimSpace(mediaType) == "application/openmetrics-text" && !refused(params) {
+			return FormatOpenMetrics
+		}
+	}
+	return FormatPrometheus
+}
+
+// refused reports whether media type parameters carry q=0
+func refused(params string) bool {
+	for _, param := range strings.Split(params, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if key == "q" {
+			q, err := strconv.ParseFloat(value, 64)
+			return err == nil && q == 0
+		}
+	}
+	return false
+}
+
+// WritePrometheus renders the metrics registry in Prometheus text format.
+// Events are aggregated per name and label set as described on Snapshot.
+func WritePrometheus(w io.Writer, registry *Registry, namespace string) error {
+	return writeExposition(w, registry, namespace, FormatPrometheus)
+}
+
+// WriteOpenMetrics renders the metrics registry in OpenMetrics text format.
+// Aggregation matches WritePrometheus; histogram buckets additionally carry
+// the latest exemplar recorded with RecordExemplar.
+func WriteOpenMetrics(w io.Writer, registry *Registry, namespace string) error {
+	return writeExposition(w, registry, namespace, FormatOpenMetrics)
+}
+
+func writeExposition(w io.Writer, registry *Registry, namespace string, format ExpositionFormat) error {
+	families, err := Snapshot(registry, namespace)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	for i := range families {
+		writeFamily(bw, &families[i], format)
+	}
+	if format == FormatOpenMetrics {
+		_, _ = io.WriteString(bw, "# EOF\n")
+	}
+	return bw.Flush()
+}
+
+func writeFamily(w io.Writer, f *MetricFamily, format ExpositionFormat) {
+	// OpenMetrics names counter families without the _total suffix that
+	// every counter sample carries
+	familyName, sampleName := f.Name, f.Name
+	if format == FormatOpenMetrics && f.Type == telemetry.TypeCounter {
+		familyName = strings.TrimSuffix(f.Name, "_total")
+		sampleName = familyName + "_total"
+	}
+
+	_, _ = fmt.Fprintf(w, "# TYPE %s %s\n", familyName, f.Type)
+	for _, series := range f.Series {
+		if f.Type != telemetry.TypeHistogram {
+			writeSample(w, sampleName, series.key, series.Value)
+			continue
+		}
+
+		for _, bucket := range series.Buckets {
+			labels := promLabels(series.Labels, "le", formatFloat(bucket.UpperBound))
+			if format == FormatOpenMetrics {
+				if ex, ok := lookupExemplar(f.source, series.key, bucket.rawLE); ok {
+					writeBucketWithExemplar(w, f.Name+"_bucket", labels, float64(bucket.Count), ex, f.scale)
+					continue
+				}
+			}
+			writeSample(w, f.Name+"_bucket", labels, float64(bucket.Count))
+		}
+		writeSample(w, f.Name+"_sum", series.key, series.Sum)
+		writeSample(w, f.Name+"_count", series.key, float64(series.Count))
+	}
+}
+
+func writeSample(w io.Writer, name, labels string, value float64) {
+	if labels != "" {
+		_, _ = fmt.Fprintf(w, "%s{%s} %s\n", name, labels, formatFloat(value))
+		return
+	}
+	_, _ = fmt.Fprintf(w, "%s %s\n", name, formatFloat(value))
+}
+
+// writeBucketWithExemplar writes a bucket sample followed by its exemplar:
+// name{labels} value # {exemplar labels} exemplar_value timestamp
+func writeBucketWithExemplar(w io.Writer, name, labels string, value float64, ex Exemplar, scale float64) {
+	_, _ = fmt.Fprintf(w, "%s{%s} %s # {%s} %s", name, labels, formatFloat(value),
+		promLabels(ex.Labels, "", ""), formatFloat(ex.Value/scale))
+	if !ex.Timestamp.IsZero() {
+		_, _ = fmt.Fprintf(w, " %s", strconv.FormatFloat(float64(ex.Timestamp.UnixMilli())/1000, 'f', 3, 64))
+	}
+	_, _ = io.WriteString(w, "\n")
+}
+
+// ExposedName returns the name a metric is scraped under with namespace applied
+func ExposedName(namespace, name string) string {
+	return promName(namespace, name)
+}
+
+// promName applies the same naming rules as the gofulmen exporter
+func promName(namespace, name string) string {
+	if namespace != "" {
+		name = namespace + "_" + name
+	}
+	name = strings.ReplaceAll(name, "-", "_")
+	name = strings.ReplaceAll(name, ".", "_")
+	return strings.ToLower(name)
+}
+
+// promLabels formats tags as a sorted label set, optionally adding one extra label
+func promLabels(tags map[string]string, extraKey, extraValue string) string {
+	keys := make([]string, 0, len(tags)+1)
+	for key := range tags {
+		if key != extraKey {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys)+1)
+	for _, key := range keys {
+		parts = append(parts, key+`="`+escapeLabelValue(tags[key])+`"`)
+	}
+	if extraKey != "" {
+		parts = append(parts, extraKey+`="`+escapeLabelValue(extraValue)+`"`)
+	}
+	return strings.Join(parts, ",")
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
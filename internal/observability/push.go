@@ -0,0 +1,235 @@
+package observability
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+)
+
+// PushErrorsMetric counts failed pushes per exporter
+const PushErrorsMetric = "metrics_push_errors_total"
+
+const (
+	defaultPushInterval = 15 * time.Second
+	defaultPushTimeout  = 5 * time.Second
+)
+
+// PushExporter sends a metrics snapshot to an external collector
+type PushExporter interface {
+	// Name identifies the exporter in logs and metric labels
+	Name() string
+
+	// Export sends the snapshot; families hold cumulative values
+	Export(ctx context.Context, families []MetricFamily) error
+}
+
+// pushLoop exports on a fixed interval until stopped
+type pushLoop struct {
+	exporter PushExporter
+	interval time.Duration
+	timeout  time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+var (
+	pushMu    sync.Mutex
+	pushLoops []*pushLoop
+)
+
+// StartPushExporters starts the exporters enabled in cfg. Each pushes on its
+// own interval; call FlushPushExporters on shutdown to send the final values.
+// InitMetrics must be called first.
+func StartPushExporters(cfg config.MetricsPushConfig) error {
+	if TelemetrySystem == nil {
+		return fmt.Errorf("metrics not initialized")
+	}
+
+	var loops []*pushLoop
+	add := func(exporter PushExporter, target config.PushExporterConfig) {
+		loops = append(loops, &pushLoop{
+			exporter: exporter,
+			interval: durationOr(target.Interval, defaultPushInterval),
+			timeout:  durationOr(target.Timeout, defaultPushTimeout),
+			stop:     make(chan struct{}),
+			done:     make(chan struct{}),
+		})
+	}
+
+	if cfg.OTLP.Enabled {
+		exporter, err := NewOTLPExporter(cfg.OTLP, metricsService)
+		if err != nil {
+			closeExporters(loops)
+			return err
+		}
+		add(exporter, cfg.OTLP)
+	}
+	if cfg.StatsD.Enabled {
+		exporter, err := NewStatsDExporter(cfg.StatsD)
+		if err != nil {
+			closeExporters(loops)
+			return err
+		}
+		add(exporter, cfg.StatsD.PushExporterConfig)
+	}
+	if cfg.RemoteWrite.Enabled {
+		exporter, err := NewRemoteWriteExporter(cfg.RemoteWrite)
+		if err != nil {
+			closeExporters(loops)
+			return err
+		}
+		add(exporter, cfg.RemoteWrite)
+	}
+
+	pushMu.Lock()
+	defer pushMu.Unlock()
+	for _, loop := range loops {
+		pushLoops = append(pushLoops, loop)
+		go loop.run()
+	}
+	return nil
+}
+
+// FlushPushExporters stops the push loops and sends one final snapshot to
+// every exporter so values recorded since the last interval are not lost
+func FlushPushExporters(ctx context.Context) error {
+	loops := stopPushLoops()
+	if len(loops) == 0 {
+		return nil
+	}
+
+	families, err := Metrics()
+	if err != nil {
+		closeExporters(loops)
+		return err
+	}
+
+	var errs []error
+	for _, loop := range loops {
+		if err := loop.exporter.Export(ctx, families); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", loop.exporter.Name(), err))
+		}
+	}
+	closeExporters(loops)
+	return errors.Join(errs...)
+}
+
+// discardPushLoops stops and closes every exporter without a final push
+func discardPushLoops() {
+	closeExporters(stopPushLoops())
+}
+
+func closeExporters(loops []*pushLoop) {
+	for _, loop := range loops {
+		if closer, ok := loop.exporter.(io.Closer); ok {
+			_ = closer.Close()
+		}
+	}
+}
+
+// stopPushLoops stops every running loop without a final push
+func stopPushLoops() []*pushLoop {
+	pushMu.Lock()
+	loops := pushLoops
+	pushLoops = nil
+	pushMu.Unlock()
+
+	for _, loop := range loops {
+		close(loop.stop)
+		<-loop.done
+	}
+	return loops
+}
+
+func (l *pushLoop) run() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			l.push()
+		}
+	}
+}
+
+func (l *pushLoop) push() {
+	families, err := Metrics()
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+		err = l.exporter.Export(ctx, families)
+		cancel()
+	}
+	if err == nil {
+		return
+	}
+
+	if sys := TelemetrySystem; sys != nil {
+		_ = sys.Counter(PushErrorsMetric, 1, map[string]string{"exporter": l.exporter.Name()})
+	}
+	if ServerLogger != nil {
+		ServerLogger.Named("metrics.push").Warn("Metrics push failed",
+			zap.String("exporter", l.exporter.Name()),
+			zap.Error(err))
+	}
+}
+
+// httpStatusError is a non-2xx response from a collector
+type httpStatusError struct {
+	endpoint string
+	status   string
+	code     int
+	body     []byte
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("%s returned %s: %s", e.endpoint, e.status, e.body)
+}
+
+// postPayload sends an encoded payload and treats any non-2xx status as an
+// *httpStatusError
+func postPayload(ctx context.Context, client *http.Client, endpoint string, headers map[string]string, body []byte, extra map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, value := range extra {
+		req.Header.Set(key, value)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &httpStatusError{endpoint: endpoint, status: resp.Status, code: resp.StatusCode, body: bytes.TrimSpace(msg)}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+func durationOr(d, fallback time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+	return fallback
+}
@@ -0,0 +1,226 @@
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fulmenhq/gofulmen/telemetry"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+)
+
+// otlpCumulative is AGGREGATION_TEMPORALITY_CUMULATIVE
+const otlpCumulative = 2
+
+// OTLPExporter pushes metrics to an OpenTelemetry collector using OTLP/HTTP
+// with the JSON encoding
+type OTLPExporter struct {
+	endpoint string
+	headers  map[string]string
+	service  string
+	client   *http.Client
+}
+
+// NewOTLPExporter creates an OTLP/HTTP exporter for cfg.Endpoint
+// (typically http://collector:4318/v1/metrics)
+func NewOTLPExporter(cfg config.PushExporterConfig, service string) (*OTLPExporter, error) {
+	if err := validateHTTPEndpoint("metrics.push.otlp", cfg.Endpoint); err != nil {
+		return nil, err
+	}
+	return &OTLPExporter{
+		endpoint: cfg.Endpoint,
+		headers:  cfg.Headers,
+		service:  service,
+		client:   &http.Client{Timeout: durationOr(cfg.Timeout, defaultPushTimeout)},
+	}, nil
+}
+
+// Name implements PushExporter
+func (e *OTLPExporter) Name() string {
+	return "otlp"
+}
+
+// Export implements PushExporter
+func (e *OTLPExporter) Export(ctx context.Context, families []MetricFamily) error {
+	body, err := json.Marshal(e.request(families, time.Now()))
+	if err != nil {
+		return err
+	}
+	return postPayload(ctx, e.client, e.endpoint, e.headers, body, map[string]string{
+		"Content-Type": "application/json",
+	})
+}
+
+// OTLP JSON mirrors the ExportMetricsServiceRequest protobuf; 64-bit
+// integers are strings as required by the protobuf JSON mapping
+type (
+	otlpRequest struct {
+		ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+	}
+	otlpResourceMetrics struct {
+		Resource     otlpResource       `json:"resource"`
+		ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+	}
+	otlpResource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	}
+	otlpScopeMetrics struct {
+		Scope   otlpScope    `json:"scope"`
+		Metrics []otlpMetric `json:"metrics"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpMetric struct {
+		Name      string         `json:"name"`
+		Unit      string         `json:"unit,omitempty"`
+		Sum       *otlpSum       `json:"sum,omitempty"`
+		Gauge     *otlpGauge     `json:"gauge,omitempty"`
+		Histogram *otlpHistogram `json:"histogram,omitempty"`
+	}
+	otlpSum struct {
+		AggregationTemporality int             `json:"aggregationTemporality"`
+		IsMonotonic            bool            `json:"isMonotonic"`
+		DataPoints             []otlpDataPoint `json:"dataPoints"`
+	}
+	otlpGauge struct {
+		DataPoints []otlpDataPoint `json:"dataPoints"`
+	}
+	otlpHistogram struct {
+		AggregationTemporality int                      `json:"aggregationTemporality"`
+		DataPoints             []otlpHistogramDataPoint `json:"dataPoints"`
+	}
+	otlpDataPoint struct {
+		Attributes        []otlpAttribute `json:"attributes,omitempty"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano,omitempty"`
+		TimeUnixNano      string          `json:"timeUnixNano"`
+		AsDouble          float64         `json:"asDouble"`
+	}
+	otlpHistogramDataPoint struct {
+		Attributes        []otlpAttribute `json:"attributes,omitempty"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano"`
+		TimeUnixNano      string          `json:"timeUnixNano"`
+		Count             string          `json:"count"`
+		Sum               float64         `json:"sum"`
+		BucketCounts      []string        `json:"bucketCounts"`
+		ExplicitBounds    []float64       `json:"explicitBounds"`
+	}
+	otlpAttribute struct {
+		Key   string       `json:"key"`
+		Value otlpAnyValue `json:"value"`
+	}
+	otlpAnyValue struct {
+		StringValue string `json:"stringValue"`
+	}
+)
+
+func (e *OTLPExporter) request(families []MetricFamily, now time.Time) otlpRequest {
+	start := strconv.FormatInt(metricsStart.UnixNano(), 10)
+	ts := strconv.FormatInt(now.UnixNano(), 10)
+
+	metrics := make([]otlpMetric, 0, len(families))
+	for _, family := range families {
+		metric := otlpMetric{Name: family.Name}
+		switch family.Type {
+		case telemetry.TypeCounter:
+			metric.Sum = &otlpSum{AggregationTemporality: otlpCumulative, IsMonotonic: true}
+			for _, series := range family.Series {
+				metric.Sum.DataPoints = append(metric.Sum.DataPoints, otlpDataPoint{
+					Attributes:        otlpAttributes(series.Labels),
+					StartTimeUnixNano: start,
+					TimeUnixNano:      ts,
+					AsDouble:          series.Value,
+				})
+			}
+		case telemetry.TypeGauge:
+			metric.Gauge = &otlpGauge{}
+			for _, series := range family.Series {
+				metric.Gauge.DataPoints = append(metric.Gauge.DataPoints, otlpDataPoint{
+					Attributes:   otlpAttributes(series.Labels),
+					TimeUnixNano: ts,
+					AsDouble:     series.Value,
+				})
+			}
+		case telemetry.TypeHistogram:
+			switch {
+			case family.scale != 1:
+				metric.Unit = "s"
+			case strings.HasSuffix(family.Name, "_ms"):
+				metric.Unit = "ms"
+			}
+			metric.Histogram = &otlpHistogram{AggregationTemporality: otlpCumulative}
+			for _, series := range family.Series {
+				metric.Histogram.DataPoints = append(metric.Histogram.DataPoints, otlpHistogramPoint(series, start, ts))
+			}
+		default:
+			continue
+		}
+		metrics = append(metrics, metric)
+	}
+
+	return otlpRequest{ResourceMetrics: []otlpResourceMetrics{{
+		Resource: otlpResource{Attributes: []otlpAttribute{
+			{Key: "service.name", Value: otlpAnyValue{StringValue: e.service}},
+		}},
+		ScopeMetrics: []otlpScopeMetrics{{
+			Scope:   otlpScope{Name: e.service},
+			Metrics: metrics,
+		}},
+	}}}
+}
+
+// otlpHistogramPoint converts cumulative buckets into OTLP's per-bucket
+// counts, where the final count belongs to the implicit +Inf bucket
+func otlpHistogramPoint(series MetricSeries, start, ts string) otlpHistogramDataPoint {
+	point := otlpHistogramDataPoint{
+		Attributes:        otlpAttributes(series.Labels),
+		StartTimeUnixNano: start,
+		TimeUnixNano:      ts,
+		Count:             strconv.FormatInt(series.Count, 10),
+		Sum:               series.Sum,
+		ExplicitBounds:    []float64{},
+	}
+
+	var previous int64
+	for _, bucket := range series.Buckets {
+		if !math.IsInf(bucket.UpperBound, 1) {
+			point.ExplicitBounds = append(point.ExplicitBounds, bucket.UpperBound)
+		}
+		point.BucketCounts = append(point.BucketCounts, strconv.FormatInt(bucket.Count-previous, 10))
+		previous = bucket.Count
+	}
+	return point
+}
+
+func otlpAttributes(labels map[string]string) []otlpAttribute {
+	if len(labels) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	attributes := make([]otlpAttribute, 0, len(keys))
+	for _, key := range keys {
+		attributes = append(attributes, otlpAttribute{Key: key, Value: otlpAnyValue{StringValue: labels[key]}})
+	}
+	return attributes
+}
+
+func validateHTTPEndpoint(key, endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s.endpoint must be an http(s) URL, got %q", key, endpoint)
+	}
+	return nil
+}
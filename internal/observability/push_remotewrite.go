@@ -0,0 +1,143 @@
+package observability
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/fulmenhq/gofulmen/telemetry"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+)
+
+// RemoteWriteExporter pushes metrics to a Prometheus remote-write receiver
+// (Prometheus with --web.enable-remote-write-receiver, Mimir, Thanos, ...)
+type RemoteWriteExporter struct {
+	endpoint string
+	headers  map[string]string
+	client   *http.Client
+}
+
+// NewRemoteWriteExporter creates a remote-write exporter for cfg.Endpoint
+func NewRemoteWriteExporter(cfg config.PushExporterConfig) (*RemoteWriteExporter, error) {
+	if err := validateHTTPEndpoint("metrics.push.remote_write", cfg.Endpoint); err != nil {
+		return nil, err
+	}
+	return &RemoteWriteExporter{
+		endpoint: cfg.Endpoint,
+		headers:  cfg.Headers,
+		client:   &http.Client{Timeout: durationOr(cfg.Timeout, defaultPushTimeout)},
+	}, nil
+}
+
+// Name implements PushExporter
+func (e *RemoteWriteExporter) Name() string {
+	return "remote_write"
+}
+
+// Export implements PushExporter
+func (e *RemoteWriteExporter) Export(ctx context.Context, families []MetricFamily) error {
+	body := snappyEncode(encodeWriteRequest(families, time.Now()))
+	return postPayload(ctx, e.client, e.endpoint, e.headers, body, map[string]string{
+		"Content-Type":                      "application/x-protobuf",
+		"Content-Encoding":                  "snappy",
+		"X-Prometheus-Remote-Write-Version": "0.1.0",
+	})
+}
+
+// encodeWriteRequest encodes a remote-write 1.0 WriteRequest. Histograms are
+// sent as the classic _bucket, _sum and _count series.
+//
+//	message WriteRequest { repeated TimeSeries timeseries = 1; }
+//	message TimeSeries   { repeated Label labels = 1; repeated Sample samples = 2; }
+//	message Label        { string name = 1; string value = 2; }
+//	message Sample       { double value = 1; int64 timestamp = 2; }
+func encodeWriteRequest(families []MetricFamily, now time.Time) []byte {
+	timestamp := now.UnixMilli()
+
+	var buf []byte
+	add := func(name string, labels map[string]string, extraKey, extraValue string, value float64) {
+		buf = appendBytesField(buf, 1, encodeTimeSeries(name, labels, extraKey, extraValue, value, timestamp))
+	}
+
+	for _, family := range families {
+		for _, series := range family.Series {
+			if family.Type != telemetry.TypeHistogram {
+				add(family.Name, series.Labels, "", "", series.Value)
+				continue
+			}
+			for _, bucket := range series.Buckets {
+				add(family.Name+"_bucket", series.Labels, "le", formatFloat(bucket.UpperBound), float64(bucket.Count))
+			}
+			add(family.Name+"_sum", series.Labels, "", "", series.Sum)
+			add(family.Name+"_count", series.Labels, "", "", float64(series.Count))
+		}
+	}
+	return buf
+}
+
+func encodeTimeSeries(name string, labels map[string]string, extraKey, extraValue string, value float64, timestamp int64) []byte {
+	all := make(map[string]string, len(labels)+2)
+	for key, v := range labels {
+		all[key] = v
+	}
+	if extraKey != "" {
+		all[extraKey] = extraValue
+	}
+	all["__name__"] = name
+
+	// Receivers require labels sorted by name
+	keys := make([]string, 0, len(all))
+	for key := range all {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var ts []byte
+	for _, key := range keys {
+		var label []byte
+		label = appendBytesField(label, 1, []byte(key))
+		label = appendBytesField(label, 2, []byte(all[key]))
+		ts = appendBytesField(ts, 1, label)
+	}
+
+	var sample []byte
+	sample = binary.AppendUvarint(sample, 1<<3|1) // field 1, fixed64
+	sample = binary.LittleEndian.AppendUint64(sample, math.Float64bits(value))
+	sample = binary.AppendUvarint(sample, 2<<3|0) // field 2, varint
+	sample = binary.AppendUvarint(sample, uint64(timestamp))
+	return appendBytesField(ts, 2, sample)
+}
+
+// appendBytesField appends a length-delimited protobuf field
+func appendBytesField(buf []byte, field int, value []byte) []byte {
+	buf = binary.AppendUvarint(buf, uint64(field)<<3|2)
+	buf = binary.AppendUvarint(buf, uint64(len(value)))
+	return append(buf, value...)
+}
+
+// snappyEncode wraps src in a snappy block made only of literal chunks. The
+// output is valid snappy that every decoder accepts; it trades compression
+// for not pulling a snappy dependency into the module.
+func snappyEncode(src []byte) []byte {
+	const maxChunk = 1 << 16
+
+	dst := binary.AppendUvarint(make([]byte, 0, len(src)+len(src)/maxChunk*3+16), uint64(len(src)))
+	for len(src) > 0 {
+		n := min(len(src), maxChunk)
+		switch {
+		case n <= 60:
+			dst = append(dst, byte(n-1)<<2)
+		case n <= 256:
+			dst = append(dst, 60<<2, byte(n-1))
+		default:
+			dst = append(dst, 61<<2, byte(n-1), byte((n-1)>>8))
+		}
+		dst = append(dst, src[:n]...)
+		src = src[n:]
+	}
+	return dst
+}
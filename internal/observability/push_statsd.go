@@ -0,0 +1,162 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/fulmenhq/gofulmen/telemetry"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+)
+
+// statsdMaxPacket keeps datagrams under a typical 1500 byte MTU
+const statsdMaxPacket = 1432
+
+// StatsDExporter sends metrics as StatsD lines over UDP. Counters and
+// histograms are sent as the change since the previous export; gauges are
+// sent as-is.
+type StatsDExporter struct {
+	conn      net.Conn
+	dogstatsd bool
+
+	mu sync.Mutex
+	// previous holds the cumulative value (counters) or count and sum
+	// (histograms) last exported per series
+	previous map[string][2]float64
+}
+
+// NewStatsDExporter creates a StatsD exporter for cfg.Endpoint (host:port)
+func NewStatsDExporter(cfg config.StatsDConfig) (*StatsDExporter, error) {
+	if _, _, err := net.SplitHostPort(cfg.Endpoint); err != nil {
+		return nil, fmt.Errorf("metrics.push.statsd.endpoint must be host:port, got %q", cfg.Endpoint)
+	}
+	conn, err := net.Dial("udp", cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("metrics.push.statsd: %w", err)
+	}
+	return &StatsDExporter{
+		conn:      conn,
+		dogstatsd: cfg.DogStatsD,
+		previous:  make(map[string][2]float64),
+	}, nil
+}
+
+// Name implements PushExporter
+func (e *StatsDExporter) Name() string {
+	return "statsd"
+}
+
+// Export implements PushExporter
+func (e *StatsDExporter) Export(ctx context.Context, families []MetricFamily) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = e.conn.SetWriteDeadline(deadline)
+	}
+
+	var packet strings.Builder
+	flush := func() error {
+		if packet.Len() == 0 {
+			return nil
+		}
+		_, err := e.conn.Write([]byte(packet.String()))
+		packet.Reset()
+		return err
+	}
+
+	for _, line := range e.lines(families) {
+		if packet.Len() > 0 && packet.Len()+1+len(line) > statsdMaxPacket {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(line)
+	}
+	return flush()
+}
+
+// Close releases the UDP socket
+func (e *StatsDExporter) Close() error {
+	return e.conn.Close()
+}
+
+func (e *StatsDExporter) lines(families []MetricFamily) []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var lines []string
+	for _, family := range families {
+		for _, series := range family.Series {
+			tags := e.tags(series.Labels)
+			id := family.Name + "{" + series.key + "}"
+			previous, seen := e.previous[id]
+
+			switch family.Type {
+			case telemetry.TypeGauge:
+				lines = append(lines, fmt.Sprintf("%s:%s|g%s", family.Name, statsdFloat(series.Value), tags))
+
+			case telemetry.TypeCounter:
+				e.previous[id] = [2]float64{series.Value}
+				delta := series.Value - previous[0]
+				if delta < 0 || !seen {
+					// First export, or the registry was reset
+					delta = series.Value
+				}
+				if delta > 0 {
+					lines = append(lines, fmt.Sprintf("%s:%s|c%s", family.Name, statsdFloat(delta), tags))
+				}
+
+			case telemetry.TypeHistogram:
+				count, sum := float64(series.Count), series.Sum
+				e.previous[id] = [2]float64{count, sum}
+				if seen && count >= previous[0] {
+					count -= previous[0]
+					sum -= previous[1]
+				}
+				if count <= 0 {
+					continue
+				}
+				// Individual observations are not retained, so the interval's
+				// mean is sent with a sample rate that restores the count
+				line := fmt.Sprintf("%s:%s|h", family.Name, statsdFloat(sum/count))
+				if count > 1 {
+					line += "|@" + statsdFloat(1/count)
+				}
+				lines = append(lines, line+tags)
+			}
+		}
+	}
+	return lines
+}
+
+// tags formats labels as DogStatsD tags, or drops them for plain StatsD
+func (e *StatsDExporter) tags(labels map[string]string) string {
+	if !e.dogstatsd || len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, key+":"+statsdTagEscaper.Replace(labels[key]))
+	}
+	return "|#" + strings.Join(parts, ",")
+}
+
+// statsdFloat formats without exponents, which some StatsD servers reject
+func statsdFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// statsdTagEscaper strips the characters that delimit DogStatsD tags and lines
+var statsdTagEscaper = strings.NewReplacer(",", "_", "|", "_", "\n", "_", "#", "_")
@@ -0,0 +1,306 @@
+package observability_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fulmenhq/gofulmen/telemetry"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+func initPushMetrics(t *testing.T) {
+	t.Helper()
+	if err := observability.InitMetrics("test", 0, "test"); err != nil {
+		t.Fatalf("failed to init metrics: %v", err)
+	}
+	t.Cleanup(func() {
+		observability.MetricsRegistry = nil
+		observability.TelemetrySystem = nil
+	})
+
+	sys := observability.TelemetrySystem
+	_ = sys.Counter("jobs_total", 3, map[string]string{"queue": "email"})
+	_ = sys.Gauge("queue_depth", 7, nil)
+	_ = sys.HistogramSummary("job_duration_ms", telemetry.HistogramSummary{
+		Count: 2,
+		Sum:   60,
+		Buckets: []telemetry.HistogramBucket{
+			{LE: 10, Count: 1},
+			{LE: 100, Count: 2},
+		},
+	}, nil)
+}
+
+// capture records the requests received by a stand-in collector
+type capture struct {
+	mu       sync.Mutex
+	headers  []http.Header
+	payloads [][]byte
+}
+
+func (c *capture) server(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		c.mu.Lock()
+		c.headers = append(c.headers, r.Header.Clone())
+		c.payloads = append(c.payloads, body)
+		c.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func (c *capture) requests() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.payloads)
+}
+
+func TestOTLPExporterPushesJSON(t *testing.T) {
+	initPushMetrics(t)
+	var got capture
+	srv := got.server(t)
+
+	exporter, err := observability.NewOTLPExporter(config.PushExporterConfig{
+		Endpoint: srv.URL + "/v1/metrics",
+		Headers:  map[string]string{"Authorization": "Bearer token"},
+	}, "test")
+	if err != nil {
+		t.Fatalf("NewOTLPExporter failed: %v", err)
+	}
+
+	families, err := observability.Metrics()
+	if err != nil {
+		t.Fatalf("Metrics failed: %v", err)
+	}
+	if err := exporter.Export(context.Background(), families); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	if ct := got.headers[0].Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected JSON content type, got %q", ct)
+	}
+	if auth := got.headers[0].Get("Authorization"); auth != "Bearer token" {
+		t.Fatalf("expected configured header, got %q", auth)
+	}
+
+	var req struct {
+		ResourceMetrics []struct {
+			ScopeMetrics []struct {
+				Metrics []struct {
+					Name string `json:"name"`
+					Unit string `json:"unit"`
+					Sum  *struct {
+						IsMonotonic bool `json:"isMonotonic"`
+						DataPoints  []struct {
+							AsDouble float64 `json:"asDouble"`
+						} `json:"dataPoints"`
+					} `json:"sum"`
+					Histogram *struct {
+						DataPoints []struct {
+							Count          string    `json:"count"`
+							BucketCounts   []string  `json:"bucketCounts"`
+							ExplicitBounds []float64 `json:"explicitBounds"`
+						} `json:"dataPoints"`
+					} `json:"histogram"`
+				} `json:"metrics"`
+			} `json:"scopeMetrics"`
+		} `json:"resourceMetrics"`
+	}
+	if err := json.Unmarshal(got.payloads[0], &req); err != nil {
+		t.Fatalf("invalid OTLP JSON: %v", err)
+	}
+
+	metrics := req.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	byName := make(map[string]int)
+	for i, m := range metrics {
+		byName[m.Name] = i
+	}
+
+	counter := metrics[byName["test_jobs_total"]]
+	if counter.Sum == nil || !counter.Sum.IsMonotonic || counter.Sum.DataPoints[0].AsDouble != 3 {
+		t.Fatalf("unexpected counter: %+v", counter)
+	}
+
+	histogram := metrics[byName["test_job_duration_ms"]]
+	point := histogram.Histogram.DataPoints[0]
+	if histogram.Unit != "ms" || point.Count != "2" {
+		t.Fatalf("unexpected histogram: %+v", histogram)
+	}
+	if strings.Join(point.BucketCounts, ",") != "1,1,0" || len(point.ExplicitBounds) != 2 || point.ExplicitBounds[1] != 100 {
+		t.Fatalf("expected per-bucket counts over millisecond bounds, got %v %v", point.BucketCounts, point.ExplicitBounds)
+	}
+}
+
+func TestRemoteWriteExporterPushesSnappyProtobuf(t *testing.T) {
+	initPushMetrics(t)
+	var got capture
+	srv := got.server(t)
+
+	exporter, err := observability.NewRemoteWriteExporter(config.PushExporterConfig{Endpoint: srv.URL + "/api/v1/write"})
+	if err != nil {
+		t.Fatalf("NewRemoteWriteExporter failed: %v", err)
+	}
+
+	families, _ := observability.Metrics()
+	if err := exporter.Export(context.Background(), families); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	headers := got.headers[0]
+	if headers.Get("Content-Encoding") != "snappy" || headers.Get("Content-Type") != "application/x-protobuf" {
+		t.Fatalf("unexpected remote-write headers: %v", headers)
+	}
+	if headers.Get("X-Prometheus-Remote-Write-Version") != "0.1.0" {
+		t.Fatalf("missing remote-write version header: %v", headers)
+	}
+
+	payload := decodeSnappyLiterals(t, got.payloads[0])
+	for _, want := range []string{"__name__", "test_jobs_total", "queue", "email", "test_job_duration_ms_bucket", "+Inf", "test_queue_depth"} {
+		if !bytes.Contains(payload, []byte(want)) {
+			t.Fatalf("expected WriteRequest to contain %q", want)
+		}
+	}
+}
+
+// decodeSnappyLiterals decodes a snappy block made of literal chunks
+func decodeSnappyLiterals(t *testing.T, block []byte) []byte {
+	t.Helper()
+	length, n := binary.Uvarint(block)
+	block = block[n:]
+
+	var out []byte
+	for len(block) > 0 {
+		tag := block[0]
+		if tag&3 != 0 {
+			t.Fatalf("unexpected non-literal snappy tag %#x", tag)
+		}
+		size := int(tag>>2) + 1
+		block = block[1:]
+		switch tag >> 2 {
+		case 60:
+			size = int(block[0]) + 1
+			block = block[1:]
+		case 61:
+			size = int(binary.LittleEndian.Uint16(block)) + 1
+			block = block[2:]
+		}
+		out = append(out, block[:size]...)
+		block = block[size:]
+	}
+	if uint64(len(out)) != length {
+		t.Fatalf("snappy length %d does not match payload %d", length, len(out))
+	}
+	return out
+}
+
+func TestStatsDExporterSendsDeltas(t *testing.T) {
+	initPushMetrics(t)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	exporter, err := observability.NewStatsDExporter(config.StatsDConfig{
+		PushExporterConfig: config.PushExporterConfig{Endpoint: conn.LocalAddr().String()},
+		DogStatsD:          true,
+	})
+	if err != nil {
+		t.Fatalf("NewStatsDExporter failed: %v", err)
+	}
+	t.Cleanup(func() { _ = exporter.Close() })
+
+	read := func() string {
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		buf := make([]byte, 2048)
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("no StatsD packet received: %v", err)
+		}
+		return string(buf[:n])
+	}
+
+	families, _ := observability.Metrics()
+	if err := exporter.Export(context.Background(), families); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	first := read()
+	for _, want := range []string{
+		"test_jobs_total:3|c|#queue:email",
+		"test_queue_depth:7|g",
+		"test_job_duration_ms:30|h|@0.5",
+	} {
+		if !strings.Contains(first, want) {
+			t.Fatalf("expected packet to contain %q, got:\n%s", want, first)
+		}
+	}
+
+	// Only the change since the previous export is sent for counters
+	_ = observability.TelemetrySystem.Counter("jobs_total", 2, map[string]string{"queue": "email"})
+	families, _ = observability.Metrics()
+	if err := exporter.Export(context.Background(), families); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	second := read()
+	if !strings.Contains(second, "test_jobs_total:2|c") || strings.Contains(second, "test_job_duration_ms") {
+		t.Fatalf("expected only deltas in second packet, got:\n%s", second)
+	}
+}
+
+func TestFlushPushExportersSendsFinalSnapshot(t *testing.T) {
+	initPushMetrics(t)
+	var got capture
+	srv := got.server(t)
+
+	err := observability.StartPushExporters(config.MetricsPushConfig{
+		OTLP: config.PushExporterConfig{
+			Enabled:  true,
+			Endpoint: srv.URL,
+			Interval: time.Hour,
+		},
+	})
+	if err != nil {
+		t.Fatalf("StartPushExporters failed: %v", err)
+	}
+
+	if err := observability.FlushPushExporters(context.Background()); err != nil {
+		t.Fatalf("FlushPushExporters failed: %v", err)
+	}
+	if n := got.requests(); n != 1 {
+		t.Fatalf("expected one push on flush, got %d", n)
+	}
+
+	// Flushing again is a no-op once the loops are stopped
+	if err := observability.FlushPushExporters(context.Background()); err != nil || got.requests() != 1 {
+		t.Fatalf("expected second flush to be a no-op, got err=%v requests=%d", err, got.requests())
+	}
+}
+
+func TestStartPushExportersRejectsInvalidEndpoints(t *testing.T) {
+	initPushMetrics(t)
+
+	for name, cfg := range map[string]config.MetricsPushConfig{
+		"otlp":         {OTLP: config.PushExporterConfig{Enabled: true, Endpoint: "collector:4318"}},
+		"remote_write": {RemoteWrite: config.PushExporterConfig{Enabled: true, Endpoint: ""}},
+		"statsd":       {StatsD: config.StatsDConfig{PushExporterConfig: config.PushExporterConfig{Enabled: true, Endpoint: "no-port"}}},
+	} {
+		if err := observability.StartPushExporters(cfg); err == nil {
+			t.Fatalf("expected %s endpoint to be rejected", name)
+		}
+	}
+}
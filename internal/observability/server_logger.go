@@ -0,0 +1,522 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fulmenhq/gofulmen/logging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+)
+
+// Default ENTERPRISE throttling, matching the gofulmen throttle middleware
+const (
+	defaultLogMaxRate   = 1000
+	defaultLogBurstSize = 100
+)
+
+// logOutput is where console sinks write; tests swap it for a buffer
+var logOutput io.Writer = os.Stderr
+
+// Logger is the server logger. It mirrors the gofulmen logging.Logger API but
+// owns its zap core, so profiles can colorize console output, fan out to
+// several sinks and run gofulmen middleware over every field.
+type Logger struct {
+	zap    *zap.Logger
+	levels *levelControl
+
+	// redactor is nil when redaction is disabled
+	redactor *Redactor
+
+	// files are the file sinks, reopened by ReopenFiles
+	files []*lumberjack.Logger
+
+	// shippers are the http sinks, flushed by Flush
+	shippers []*httpShipper
+}
+
+// NewLogger builds a logger for cfg.Profile (SIMPLE, STRUCTURED or
+// ENTERPRISE; case-insensitive, STRUCTURED when empty). fields are attached to
+// every entry after service and environment.
+func NewLogger(serviceName string, cfg config.LoggingConfig, fields ...zap.Field) (*Logger, error) {
+	profile := logging.LoggingProfile(strings.ToUpper(cfg.Profile))
+	if profile == "" {
+		profile = logging.ProfileStructured
+	}
+
+	sinks, middleware, err := profilePlan(profile, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	redactor, err := NewRedactor(cfg.Redaction)
+	if err != nil {
+		return nil, err
+	}
+
+	levels := newLevelControl(logging.ParseSeverity(parseLogLevel(cfg.Level)).ToZapLevel())
+	cores := make([]zapcore.Core, 0, len(sinks))
+	var files []*lumberjack.Logger
+	var shippers []*httpShipper
+	for i, sink := range sinks {
+		core, output, err := newSinkCore(serviceName, sink)
+		if err != nil {
+			for _, shipper := range shippers {
+				_ = shipper.close(context.Background())
+			}
+			return nil, fmt.Errorf("logging.sinks[%d]: %w", i, err)
+		}
+		cores = append(cores, core)
+		switch output := output.(type) {
+		case *lumberjack.Logger:
+			files = append(files, output)
+		case *httpShipper:
+			shippers = append(shippers, output)
+		}
+	}
+	core := zapcore.NewTee(cores...)
+
+	environment := cfg.Environment
+	if environment == "" {
+		environment = "production"
+	}
+	if len(middleware) > 0 {
+		core = &pipelineCore{
+			next:     core,
+			pipeline: logging.NewMiddlewarePipeline(middleware),
+			envelope: &logging.LoggerConfig{Service: serviceName, Environment: environment},
+		}
+	}
+	// Redact first so middleware and sinks never see the original values
+	if redactor != nil {
+		core = &redactCore{next: core, redactor: redactor}
+	}
+	// Levels are decided before middleware runs, so throttling only counts
+	// events that would be written
+	core = &levelCore{Core: core, levels: levels}
+
+	staticFields := append([]zap.Field{
+		zap.String("service", serviceName),
+		zap.String("environment", environment),
+	}, fields...)
+	options := []zap.Option{zap.Fields(staticFields...)}
+	if profile != logging.ProfileSimple {
+		options = append(options, zap.AddStacktrace(zapcore.ErrorLevel))
+	}
+
+	return &Logger{
+		zap:      zap.New(core, options...),
+		levels:   levels,
+		redactor: redactor,
+		files:    files,
+		shippers: shippers,
+	}, nil
+}
+
+// profilePlan resolves the sinks and middleware a profile runs with, and
+// rejects settings the profile does not support
+func profilePlan(profile logging.LoggingProfile, cfg config.LoggingConfig) ([]config.LogSinkConfig, []logging.Middleware, error) {
+	jsonConsole := []config.LogSinkConfig{{Type: "console", Format: "json"}}
+
+	switch profile {
+	case logging.ProfileSimple:
+		if len(cfg.Sinks) > 0 || len(cfg.Middleware) > 0 || cfg.Throttling.Enabled {
+			return nil, nil, fmt.Errorf("logging profile SIMPLE does not support sinks, middleware or throttling; use STRUCTURED or ENTERPRISE")
+		}
+		return []config.LogSinkConfig{{Type: "console", Format: "console", Colorize: colorTerminal()}}, nil, nil
+
+	case logging.ProfileStructured:
+		if len(cfg.Sinks) > 1 {
+			return nil, nil, fmt.Errorf("logging profile STRUCTURED supports one sink, got %d; use ENTERPRISE for more", len(cfg.Sinks))
+		}
+		if len(cfg.Middleware) > 0 {
+			return nil, nil, fmt.Errorf("logging.middleware requires the ENTERPRISE profile")
+		}
+		sinks := cfg.Sinks
+		if len(sinks) == 0 {
+			sinks = jsonConsole
+		}
+		middleware, err := logMiddleware(nil, cfg.Throttling)
+		return sinks, middleware, err
+
+	case logging.ProfileEnterprise:
+		sinks := cfg.Sinks
+		if len(sinks) == 0 {
+			sinks = jsonConsole
+		}
+		middleware, err := logMiddleware(cfg.Middleware, cfg.Throttling)
+		return sinks, middleware, err
+
+	default:
+		return nil, nil, fmt.Errorf("unknown logging profile %q (want SIMPLE, STRUCTURED or ENTERPRISE)", profile)
+	}
+}
+
+// logMiddleware creates the configured middleware from the gofulmen registry,
+// plus correlation IDs (unless configured explicitly) and throttling
+func logMiddleware(configured []config.LogMiddlewareConfig, throttling config.LogThrottlingConfig) ([]logging.Middleware, error) {
+	var middleware []logging.Middleware
+	correlation := false
+	for i, mw := range configured {
+		settings := make(map[string]any, len(mw.Config)+1)
+		for key, value := range mw.Config {
+			settings[key] = value
+		}
+		if mw.Order != 0 {
+			settings["order"] = mw.Order
+		}
+
+		created, err := logging.DefaultRegistry().Create(mw.Type, settings)
+		if err != nil {
+			return nil, fmt.Errorf("logging.middleware[%d]: %w", i, err)
+		}
+		middleware = append(middleware, created)
+		correlation = correlation || mw.Type == "correlation"
+	}
+
+	if !correlation {
+		created, err := logging.NewCorrelationMiddleware(map[string]any{})
+		if err != nil {
+			return nil, err
+		}
+		middleware = append(middleware, created)
+	}
+
+	if throttling.Enabled {
+		settings := map[string]any{"dropPolicy": logging.DropPolicyNewest}
+		settings["maxRate"] = defaultLogMaxRate
+		if throttling.MaxRate > 0 {
+			settings["maxRate"] = throttling.MaxRate
+		}
+		settings["burstSize"] = defaultLogBurstSize
+		if throttling.BurstSize > 0 {
+			settings["burstSize"] = throttling.BurstSize
+		}
+		created, err := logging.NewThrottlingMiddleware(settings)
+		if err != nil {
+			return nil, err
+		}
+		middleware = append(middleware, severityThrottle{created})
+	}
+
+	return middleware, nil
+}
+
+// LogEventsDroppedMetric counts log events dropped by throttling
+const LogEventsDroppedMetric = "log_events_dropped_total"
+
+// severityThrottle runs the gofulmen throttle for events below ERROR only:
+// errors always reach the sinks and use no tokens. Drops are counted in
+// LogEventsDroppedMetric{severity}.
+type severityThrottle struct {
+	logging.Middleware
+}
+
+func (t severityThrottle) Process(event *logging.LogEvent) *logging.LogEvent {
+	if event == nil || event.Severity.Level() >= logging.ERROR.Level() {
+		return event
+	}
+	if kept := t.Middleware.Process(event); kept != nil {
+		return kept
+	}
+	if sys := TelemetrySystem; sys != nil {
+		_ = sys.Counter(LogEventsDroppedMetric, 1, map[string]string{"severity": string(event.Severity)})
+	}
+	return nil
+}
+
+// newSinkCore builds the zap core for one sink, returning its output so
+// rotating files and http shippers can be tracked. A sink level only raises the floor; the logger's levels
+// (levelCore) still apply, so runtime level changes reach every sink.
+// serviceName identifies syslog and journald entries.
+func newSinkCore(serviceName string, sink config.LogSinkConfig) (zapcore.Core, io.Writer, error) {
+	enabler := zapcore.DebugLevel
+	if sink.Level != "" {
+		enabler = logging.ParseSeverity(parseLogLevel(sink.Level)).ToZapLevel()
+	}
+
+	var output io.Writer
+	facility := -1
+	switch sink.Type {
+	case "", "console":
+		output = logOutput
+	case "file":
+		file, err := newLogFile(sink)
+		if err != nil {
+			return nil, nil, err
+		}
+		output = file
+	case "syslog":
+		var err error
+		if facility, err = syslogFacility(sink.Facility); err != nil {
+			return nil, nil, err
+		}
+		writer, err := newSyslogWriter(sink.Address)
+		if err != nil {
+			return nil, nil, err
+		}
+		output = writer
+	case "journald":
+		core, err := newJournalCore(sink.Address, serviceName, enabler)
+		return core, nil, err
+	case "http":
+		shipper, err := newHTTPShipper(sink)
+		if err != nil {
+			return nil, nil, err
+		}
+		output = shipper
+	default:
+		return nil, nil, fmt.Errorf("unknown sink type %q (want console, file, syslog, journald or http)", sink.Type)
+	}
+
+	encoderConfig := logEncoderConfig()
+	var encoder zapcore.Encoder
+	switch sink.Format {
+	case "", "json":
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	case "console":
+		if sink.Colorize {
+			encoderConfig.EncodeLevel = colorSeverityEncoder
+		}
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	default:
+		return nil, nil, fmt.Errorf("unknown sink format %q (want json or console)", sink.Format)
+	}
+	if facility >= 0 {
+		encoder = newSyslogEncoder(encoder, facility, serviceName)
+	}
+
+	return zapcore.NewCore(encoder, zapcore.Lock(zapcore.AddSync(output)), enabler), output, nil
+}
+
+// newLogFile opens a rotating log file. The file is created up front so a bad
+// path or permission fails startup instead of every write.
+func newLogFile(sink config.LogSinkConfig) (*lumberjack.Logger, error) {
+	if sink.Path == "" {
+		return nil, fmt.Errorf("file sink requires a path")
+	}
+	if sink.MaxSizeMB < 0 || sink.MaxAgeDays < 0 || sink.MaxBackups < 0 {
+		return nil, fmt.Errorf("file sink %s: max_size_mb, max_age_days and max_backups must not be negative", sink.Path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(sink.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("file sink %s: %w", sink.Path, err)
+	}
+	f, err := os.OpenFile(sink.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("file sink %s: %w", sink.Path, err)
+	}
+	_ = f.Close()
+
+	return &lumberjack.Logger{
+		Filename:   sink.Path,
+		MaxSize:    sink.MaxSizeMB,
+		MaxAge:     sink.MaxAgeDays,
+		MaxBackups: sink.MaxBackups,
+		Compress:   sink.Compress,
+	}, nil
+}
+
+// OpenLogFile opens a rotating log file for writers outside the server logger,
+// such as the Common Log Format access log
+func OpenLogFile(path string) (*lumberjack.Logger, error) {
+	return newLogFile(config.LogSinkConfig{Type: "file", Path: path})
+}
+
+// logEncoderConfig uses the gofulmen field names so output stays compatible
+// with the CLI logger and log pipelines built for it
+func logEncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        "timestamp",
+		LevelKey:       "severity",
+		NameKey:        "logger",
+		MessageKey:     "message",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    severityEncoder,
+		EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+}
+
+// severityName maps zap levels to Fulmen severities (zap has no TRACE)
+func severityName(l zapcore.Level) string {
+	switch l {
+	case zapcore.DebugLevel:
+		return "DEBUG"
+	case zapcore.WarnLevel:
+		return "WARN"
+	case zapcore.ErrorLevel:
+		return "ERROR"
+	case zapcore.DPanicLevel, zapcore.PanicLevel, zapcore.FatalLevel:
+		return "FATAL"
+	default:
+		return "INFO"
+	}
+}
+
+func severityEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString(severityName(l))
+}
+
+// colorSeverityEncoder wraps the severity in ANSI colors for terminals
+func colorSeverityEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	color := "34" // blue
+	switch {
+	case l == zapcore.DebugLevel:
+		color = "35" // magenta
+	case l == zapcore.WarnLevel:
+		color = "33" // yellow
+	case l >= zapcore.ErrorLevel:
+		color = "31" // red
+	}
+	enc.AppendString("\x1b[" + color + "m" + severityName(l) + "\x1b[0m")
+}
+
+// colorTerminal reports whether SIMPLE output should be colorized: stderr is a
+// terminal and NO_COLOR (https://no-color.org) is unset
+func colorTerminal() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	file, ok := logOutput.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Trace logs at TRACE level (DEBUG in zap)
+func (l *Logger) Trace(msg string, fields ...zap.Field) {
+	l.zap.Debug(msg, fields...)
+}
+
+// Debug logs at DEBUG level
+func (l *Logger) Debug(msg string, fields ...zap.Field) {
+	l.zap.Debug(msg, fields...)
+}
+
+// Info logs at INFO level
+func (l *Logger) Info(msg string, fields ...zap.Field) {
+	l.zap.Info(msg, fields...)
+}
+
+// Warn logs at WARN level
+func (l *Logger) Warn(msg string, fields ...zap.Field) {
+	l.zap.Warn(msg, fields...)
+}
+
+// Error logs at ERROR level
+func (l *Logger) Error(msg string, fields ...zap.Field) {
+	l.zap.Error(msg, fields...)
+}
+
+// Fatal logs at FATAL level and exits
+func (l *Logger) Fatal(msg string, fields ...zap.Field) {
+	l.zap.Fatal(msg, fields...)
+}
+
+// With returns a logger that adds fields to every entry
+func (l *Logger) With(fields ...zap.Field) *Logger {
+	return &Logger{zap: l.zap.With(fields...), levels: l.levels, redactor: l.redactor, files: l.files, shippers: l.shippers}
+}
+
+// WithFields returns a logger that adds the map entries to every entry
+func (l *Logger) WithFields(fields map[string]any) *Logger {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	zapFields := make([]zap.Field, len(keys))
+	for i, key := range keys {
+		zapFields[i] = zap.Any(key, fields[key])
+	}
+	return l.With(zapFields...)
+}
+
+// Named returns a logger with the specified name
+func (l *Logger) Named(name string) *Logger {
+	return &Logger{zap: l.zap.Named(name), levels: l.levels, redactor: l.redactor, files: l.files, shippers: l.shippers}
+}
+
+// Sync flushes any buffered log entries
+func (l *Logger) Sync() error {
+	return l.zap.Sync()
+}
+
+// Flush delivers what http sinks have queued or buffered on disk and stops
+// them, within ctx. Entries that cannot be delivered stay in the disk buffer
+// for the next start; the error reports them. Entries logged afterwards go
+// to the disk buffer (or are dropped without one). Call it once, on shutdown.
+func (l *Logger) Flush(ctx context.Context) error {
+	var errs []error
+	for _, shipper := range l.shippers {
+		if err := shipper.close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ReopenFiles closes the file sinks; the next write reopens each path. Call it
+// after logrotate moves (create) or truncates (copytruncate) a log file so
+// writes go to the new file and the size count starts over.
+func (l *Logger) ReopenFiles() error {
+	var errs []error
+	for _, file := range l.files {
+		if err := file.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("reopen %s: %w", file.Filename, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SetLevel changes the global level of this logger and every logger derived
+// from it, ending any temporary global override
+func (l *Logger) SetLevel(severity logging.Severity) {
+	l.levels.set("", severity.ToZapLevel(), 0)
+}
+
+// SetConfiguredLevel applies the level from config, e.g. on reload. A
+// temporary global override stays active and reverts to this level.
+func (l *Logger) SetConfiguredLevel(severity logging.Severity) {
+	l.levels.setPersistent("", severity.ToZapLevel())
+}
+
+// SetComponentLevel changes the level of one component, the name given to
+// Named ("" for global). Components inherit overrides from their parents:
+// "http" also covers "http.client". With ttl > 0 the change reverts after ttl.
+func (l *Logger) SetComponentLevel(component string, severity logging.Severity, ttl time.Duration) {
+	l.levels.set(component, severity.ToZapLevel(), ttl)
+}
+
+// ResetComponentLevel removes a component override so it follows the global level
+func (l *Logger) ResetComponentLevel(component string) {
+	if component != "" {
+		l.levels.reset(component)
+	}
+}
+
+// GetLevel returns the current global log level
+func (l *Logger) GetLevel() logging.Severity {
+	return logging.Severity(severityName(l.levels.globalLevel()))
+}
+
+// Levels returns the global level and every component override
+func (l *Logger) Levels() LevelStatus {
+	return l.levels.status()
+}
@@ -0,0 +1,264 @@
+package observability_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fulmenhq/gofulmen/logging"
+	"go.uber.org/zap"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+func newTestLogger(t *testing.T, cfg config.LoggingConfig) (*observability.Logger, *bytes.Buffer) {
+	t.Helper()
+	var buf bytes.Buffer
+	t.Cleanup(observability.SetLogOutput(&buf))
+
+	logger, err := observability.NewLogger("test-service", cfg, zap.String("namespace", "test"))
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+	return logger, &buf
+}
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("log line is not JSON: %q", line)
+		}
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestStructuredProfileWritesJSONWithEnvironment(t *testing.T) {
+	logger, buf := newTestLogger(t, config.LoggingConfig{Level: "info", Profile: "structured", Environment: "staging"})
+
+	logger.Debug("hidden")
+	logger.Info("hello", zap.String("component", "test"))
+
+	lines := decodeLines(t, buf)
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want 1 (debug is below info)", len(lines))
+	}
+	entry := lines[0]
+	for key, want := range map[string]any{
+		"severity":    "INFO",
+		"message":     "hello",
+		"service":     "test-service",
+		"environment": "staging",
+		"namespace":   "test",
+		"component":   "test",
+	} {
+		if entry[key] != want {
+			t.Errorf("%s = %v, want %v", key, entry[key], want)
+		}
+	}
+	if id, _ := entry["correlationId"].(string); id == "" {
+		t.Error("STRUCTURED should add a correlationId")
+	}
+	if strings.Count(buf.String(), `"service"`) != 1 {
+		t.Errorf("fields are duplicated: %s", buf.String())
+	}
+}
+
+func TestSimpleProfileWritesConsoleText(t *testing.T) {
+	logger, buf := newTestLogger(t, config.LoggingConfig{Level: "info", Profile: "SIMPLE"})
+
+	logger.Warn("disk almost full", zap.Int("percent", 91))
+
+	out := buf.String()
+	if strings.HasPrefix(out, "{") {
+		t.Fatalf("SIMPLE should not write JSON: %q", out)
+	}
+	if !strings.Contains(out, "\tWARN\tdisk almost full\t") || !strings.Contains(out, `"percent": 91`) {
+		t.Errorf("unexpected console line: %q", out)
+	}
+	if strings.Contains(out, "\x1b[") {
+		t.Errorf("SIMPLE should only colorize terminals: %q", out)
+	}
+}
+
+func TestColorizedConsoleSink(t *testing.T) {
+	logger, buf := newTestLogger(t, config.LoggingConfig{
+		Level:   "info",
+		Profile: "STRUCTURED",
+		Sinks:   []config.LogSinkConfig{{Type: "console", Format: "console", Colorize: true}},
+	})
+
+	logger.Error("boom")
+
+	if !strings.Contains(buf.String(), "\x1b[31mERROR\x1b[0m") {
+		t.Errorf("expected a red ERROR severity: %q", buf.String())
+	}
+}
+
+func TestEnterpriseProfileSinksAndMiddleware(t *testing.T) {
+	logger, buf := newTestLogger(t, config.LoggingConfig{
+		Level:   "debug",
+		Profile: "ENTERPRISE",
+		Sinks: []config.LogSinkConfig{
+			{Type: "console", Format: "json"},
+			{Type: "console", Format: "json", Level: "warn"},
+		},
+		Middleware: []config.LogMiddlewareConfig{{Type: "redact-secrets"}},
+	})
+
+	logger.With(zap.String("password", "hunter22")).Info("login", zap.String("user", "ada"))
+	logger.Warn("slow")
+
+	lines := decodeLines(t, buf)
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3 (info to one sink, warn to both)", len(lines))
+	}
+	if lines[0]["password"] != "[REDACTED]" {
+		t.Errorf("password = %v, want redacted (With fields must pass through middleware)", lines[0]["password"])
+	}
+	if lines[0]["user"] != "ada" {
+		t.Errorf("user = %v, want untouched", lines[0]["user"])
+	}
+}
+
+func TestEnterpriseProfileThrottles(t *testing.T) {
+	if err := observability.InitMetrics("test", 0, "test"); err != nil {
+		t.Fatalf("failed to init metrics: %v", err)
+	}
+	t.Cleanup(func() {
+		observability.MetricsRegistry = nil
+		observability.TelemetrySystem = nil
+	})
+
+	logger, buf := newTestLogger(t, config.LoggingConfig{
+		Level:      "info",
+		Profile:    "ENTERPRISE",
+		Throttling: config.LogThrottlingConfig{Enabled: true, MaxRate: 1, BurstSize: 2},
+	})
+
+	for range 10 {
+		logger.Info("flood")
+	}
+	for range 3 {
+		logger.Error("failure")
+	}
+
+	lines := decodeLines(t, buf)
+	if len(lines) != 5 {
+		t.Fatalf("got %d lines, want the burst of 2 plus every error", len(lines))
+	}
+	for _, entry := range lines[2:] {
+		if entry["severity"] != "ERROR" {
+			t.Errorf("expected errors after the burst, got %v", entry)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := observability.WritePrometheus(&out, observability.MetricsRegistry, "test"); err != nil {
+		t.Fatalf("WritePrometheus failed: %v", err)
+	}
+	if want := `test_log_events_dropped_total{severity="INFO"} 8`; !strings.Contains(out.String(), want) {
+		t.Errorf("expected %q in metrics, got:\n%s", want, out.String())
+	}
+}
+
+func TestEnterpriseProfileHonorsThrottlingDisabled(t *testing.T) {
+	logger, buf := newTestLogger(t, config.LoggingConfig{
+		Level:      "info",
+		Profile:    "ENTERPRISE",
+		Throttling: config.LogThrottlingConfig{Enabled: false, MaxRate: 1, BurstSize: 2},
+	})
+
+	for range 10 {
+		logger.Info("flood")
+	}
+
+	if got := len(decodeLines(t, buf)); got != 10 {
+		t.Errorf("got %d lines, want all 10 with throttling disabled", got)
+	}
+}
+
+func TestFileSinkReopensAfterRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "server.log")
+	logger, err := observability.NewLogger("test-service", config.LoggingConfig{
+		Level:   "info",
+		Profile: "ENTERPRISE",
+		Sinks: []config.LogSinkConfig{
+			{Type: "file", Path: path, MaxSizeMB: 1, MaxBackups: 2, Compress: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("file sink should create the file at startup: %v", err)
+	}
+
+	logger.Info("before rotation")
+	// logrotate "create": move the file away, then signal a reopen
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.ReopenFiles(); err != nil {
+		t.Fatalf("ReopenFiles: %v", err)
+	}
+	logger.Info("after rotation")
+	t.Cleanup(func() { _ = logger.ReopenFiles() })
+
+	rotated, _ := os.ReadFile(path + ".1")
+	current, _ := os.ReadFile(path)
+	if !strings.Contains(string(rotated), "before rotation") || strings.Contains(string(rotated), "after rotation") {
+		t.Errorf("rotated file = %q", rotated)
+	}
+	if !strings.Contains(string(current), `"message":"after rotation"`) {
+		t.Errorf("reopened file = %q", current)
+	}
+}
+
+func TestLoggerSetLevel(t *testing.T) {
+	logger, buf := newTestLogger(t, config.LoggingConfig{Level: "warn"})
+
+	logger.Info("dropped")
+	logger.SetLevel(logging.DEBUG)
+	logger.Named("worker").Debug("kept")
+
+	if logger.GetLevel() != logging.DEBUG {
+		t.Errorf("GetLevel = %s, want DEBUG", logger.GetLevel())
+	}
+	lines := decodeLines(t, buf)
+	if len(lines) != 1 || lines[0]["message"] != "kept" || lines[0]["logger"] != "worker" {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestNewLoggerRejectsInvalidProfiles(t *testing.T) {
+	tests := map[string]config.LoggingConfig{
+		"unknown profile":        {Profile: "VERBOSE"},
+		"SIMPLE with sinks":      {Profile: "SIMPLE", Sinks: []config.LogSinkConfig{{Type: "console"}}},
+		"SIMPLE with throttling": {Profile: "SIMPLE", Throttling: config.LogThrottlingConfig{Enabled: true}},
+		"STRUCTURED two sinks":   {Profile: "STRUCTURED", Sinks: []config.LogSinkConfig{{}, {}}},
+		"STRUCTURED middleware":  {Profile: "STRUCTURED", Middleware: []config.LogMiddlewareConfig{{Type: "redact-pii"}}},
+		"unknown middleware":     {Profile: "ENTERPRISE", Middleware: []config.LogMiddlewareConfig{{Type: "nope"}}},
+		"unknown sink type":      {Profile: "ENTERPRISE", Sinks: []config.LogSinkConfig{{Type: "kafka"}}},
+		"file sink without path": {Profile: "STRUCTURED", Sinks: []config.LogSinkConfig{{Type: "file"}}},
+		"file sink in a file":    {Profile: "STRUCTURED", Sinks: []config.LogSinkConfig{{Type: "file", Path: "/dev/null/x.log"}}},
+		"unknown sink format":    {Profile: "ENTERPRISE", Sinks: []config.LogSinkConfig{{Format: "xml"}}},
+	}
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := observability.NewLogger("test", cfg); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package observability
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fulmenhq/gofulmen/telemetry"
+)
+
+// MetricFamily is the aggregated state of every series sharing a metric name.
+// Families are what the text renderers and push exporters consume.
+type MetricFamily struct {
+	// Name is the exported name (namespace prefix applied)
+	Name string
+	Type telemetry.MetricType
+
+	// Series are sorted by label set
+	Series []MetricSeries
+
+	source string
+	scale  float64
+}
+
+// MetricSeries is one labelled series. Counters and gauges use Value;
+// histograms use Count, Sum and Buckets. Histograms are recorded in
+// milliseconds; `_seconds` histograms are converted to seconds and `_ms`
+// ones keep milliseconds, so values always match the name.
+type MetricSeries struct {
+	Labels  map[string]string
+	Value   float64
+	Count   int64
+	Sum     float64
+	Buckets []MetricBucket
+
+	key string
+}
+
+// MetricBucket is a cumulative histogram bucket. The last bucket is +Inf.
+type MetricBucket struct {
+	UpperBound float64
+	Count      int64
+
+	rawLE float64
+}
+
+// seriesState accumulates the state of one labelled series
+type seriesState struct {
+	labels  map[string]string
+	value   float64
+	count   int64
+	sum     float64
+	buckets map[float64]int64
+}
+
+// familyState groups the series that share a metric name and type
+type familyState struct {
+	source     string
+	metricType telemetry.MetricType
+	toSeconds  bool
+	series     map[string]*seriesState
+}
+
+// Registry is the in-process metrics store. It implements
+// telemetry.MetricsEmitter and aggregates every event as it is recorded:
+// counters are summed, gauges keep their latest value and histogram
+// observations are merged into cumulative buckets. Memory is bounded by the
+// number of series, not the number of events, so scrapes cost the same
+// however long the process has been running.
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*familyState
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*familyState)}
+}
+
+// Counter implements telemetry.MetricsEmitter
+func (r *Registry) Counter(name string, value float64, tags map[string]string) error {
+	return r.record(name, telemetry.TypeCounter, tags, func(s *seriesState) { s.value += value })
+}
+
+// Gauge implements telemetry.MetricsEmitter
+func (r *Registry) Gauge(name string, value float64, tags map[string]string) error {
+	return r.record(name, telemetry.TypeGauge, tags, func(s *seriesState) { s.value = value })
+}
+
+// Histogram implements telemetry.MetricsEmitter. The duration is observed in
+// milliseconds against the ADR-0007 buckets.
+func (r *Registry) Histogram(name string, duration time.Duration, tags map[string]string) error {
+	value := float64(duration.Nanoseconds()) / 1e6
+	return r.record(name, telemetry.TypeHistogram, tags, func(s *seriesState) {
+		s.count++
+		s.sum += value
+		for _, le := range telemetry.DefaultHistogramBucketsMS {
+			if value <= le {
+				s.buckets[le]++
+			} else if _, ok := s.buckets[le]; !ok {
+				s.buckets[le] = 0
+			}
+		}
+		s.buckets[math.Inf(1)]++
+	})
+}
+
+// HistogramSummary implements telemetry.MetricsEmitter
+func (r *Registry) HistogramSummary(name string, summary telemetry.HistogramSummary, tags map[string]string) error {
+	return r.record(name, telemetry.TypeHistogram, tags, func(s *seriesState) {
+		s.count += summary.Count
+		s.sum += summary.Sum
+		for _, bucket := range summary.Buckets {
+			s.buckets[bucket.LE] += bucket.Count
+		}
+	})
+}
+
+func (r *Registry) record(name string, metricType telemetry.MetricType, tags map[string]string, observe func(*seriesState)) error {
+	key := promLabels(tags, "", "")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	family, ok := r.families[name]
+	if !ok {
+		family = &familyState{
+			source:     name,
+			metricType: metricType,
+			toSeconds:  strings.HasSuffix(name, "_seconds"),
+			series:     make(map[string]*seriesState),
+		}
+		r.families[name] = family
+	}
+	if family.metricType != metricType {
+		// A name reused with a different type would produce invalid output
+		return fmt.Errorf("metric %s is a %s, not a %s", name, family.metricType, metricType)
+	}
+
+	series, ok := family.series[key]
+	if !ok {
+		labels := make(map[string]string, len(tags))
+		for label, value := range tags {
+			labels[label] = value
+		}
+		series = &seriesState{labels: labels, buckets: make(map[float64]int64)}
+		family.series[key] = series
+	}
+	observe(series)
+	return nil
+}
+
+// Snapshot returns the current state of every family in registry, sorted by
+// exported name
+func Snapshot(registry *Registry, namespace string) ([]MetricFamily, error) {
+	if registry == nil {
+		return nil, fmt.Errorf("metrics registry not initialized")
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	snapshot := make([]MetricFamily, 0, len(registry.families))
+	for _, family := range registry.families {
+		snapshot = append(snapshot, family.freeze(promName(namespace, family.source)))
+	}
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].Name < snapshot[j].Name })
+	return snapshot, nil
+}
+
+// freeze converts the accumulated state into a sorted, unit-converted family
+func (f *familyState) freeze(name string) MetricFamily {
+	family := MetricFamily{
+		Name:   name,
+		Type:   f.metricType,
+		Series: make([]MetricSeries, 0, len(f.series)),
+		source: f.source,
+		scale:  1,
+	}
+	if f.toSeconds {
+		family.scale = 1000
+	}
+
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		state := f.series[key]
+		series := MetricSeries{Labels: copyLabels(state.labels), Value: state.value, key: key}
+		if f.metricType == telemetry.TypeHistogram {
+			series.Count = state.count
+			series.Sum = state.sum / family.scale
+
+			bounds := make([]float64, 0, len(state.buckets)+1)
+			for le := range state.buckets {
+				bounds = append(bounds, le)
+			}
+			_, hasInf := state.buckets[math.Inf(1)]
+			if !hasInf {
+				bounds = append(bounds, math.Inf(1))
+			}
+			sort.Float64s(bounds)
+
+			series.Buckets = make([]MetricBucket, 0, len(bounds))
+			for _, le := range bounds {
+				upper := le
+				if !math.IsInf(le, 1) {
+					upper = le / family.scale
+				}
+				count := state.buckets[le]
+				if math.IsInf(le, 1) && !hasInf {
+					count = state.count
+				}
+				series.Buckets = append(series.Buckets, MetricBucket{UpperBound: upper, Count: count, rawLE: le})
+			}
+		}
+		family.Series = append(family.Series, series)
+	}
+	return family
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	out := make(map[string]string, len(labels))
+	for label, value := range labels {
+		out[label] = value
+	}
+	return out
+}
+
+// Metrics runs the registered collectors and returns an aggregated snapshot
+// of the metrics registry
+func Metrics() ([]MetricFamily, error) {
+	Collect()
+	return Snapshot(MetricsRegistry, metricsNamespace)
+}
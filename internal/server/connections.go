@@ -0,0 +1,54 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"sync"
+)
+
+// connTracker follows http.Server.ConnState transitions to count open,
+// active and idle connections
+type connTracker struct {
+	mu     sync.Mutex
+	states map[net.Conn]http.ConnState
+	active int64
+	idle   int64
+}
+
+func newConnTracker() *connTracker {
+	return &connTracker{states: make(map[net.Conn]http.ConnState)}
+}
+
+// track is installed as http.Server.ConnState
+func (t *connTracker) track(conn net.Conn, state http.ConnState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if previous, ok := t.states[conn]; ok {
+		t.adjust(previous, -1)
+	}
+
+	switch state {
+	case http.StateClosed, http.StateHijacked:
+		delete(t.states, conn)
+	default:
+		t.states[conn] = state
+		t.adjust(state, 1)
+	}
+}
+
+func (t *connTracker) adjust(state http.ConnState, delta int64) {
+	switch state {
+	case http.StateActive:
+		t.active += delta
+	case http.StateIdle:
+		t.idle += delta
+	}
+}
+
+// counts returns open (new, active or idle), active and idle connection counts
+func (t *connTracker) counts() (open, active, idle int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return int64(len(t.states)), t.active, t.idle
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fulmenhq/gofulmen/telemetry"
+	telemetrytesting "github.com/fulmenhq/gofulmen/telemetry/testing"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/metrics"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+func TestConnTrackerCountsStates(t *testing.T) {
+	tracker := newConnTracker()
+	a, b := net.Pipe()
+	defer func() { _ = a.Close(); _ = b.Close() }()
+
+	tracker.track(a, http.StateNew)
+	tracker.track(b, http.StateNew)
+	tracker.track(a, http.StateActive)
+	tracker.track(b, http.StateActive)
+	tracker.track(b, http.StateIdle)
+
+	if open, active, idle := tracker.counts(); open != 2 || active != 1 || idle != 1 {
+		t.Fatalf("expected open=2 active=1 idle=1, got open=%d active=%d idle=%d", open, active, idle)
+	}
+
+	tracker.track(a, http.StateClosed)
+	tracker.track(b, http.StateHijacked)
+
+	if open, active, idle := tracker.counts(); open != 0 || active != 0 || idle != 0 {
+		t.Fatalf("expected all counts to drop to zero, got open=%d active=%d idle=%d", open, active, idle)
+	}
+}
+
+func TestCollectMetricsEmitsServerGauges(t *testing.T) {
+	collector := telemetrytesting.NewFakeCollector()
+	sys, err := telemetry.NewSystem(&telemetry.Config{Enabled: true, Emitter: collector})
+	if err != nil {
+		t.Fatalf("failed to create telemetry system: %v", err)
+	}
+	original := observability.TelemetrySystem
+	observability.TelemetrySystem = sys
+	t.Cleanup(func() { observability.TelemetrySystem = original })
+
+	srv := New("127.0.0.1", 0)
+	srv.startTime = time.Now().Add(-90 * time.Second)
+	a, _ := net.Pipe()
+	srv.conns.track(a, http.StateActive)
+
+	srv.collectMetrics()
+
+	values := map[string]float64{}
+	for _, m := range collector.GetMetrics() {
+		value, _ := m.Value.(float64)
+		values[m.Name] = value
+	}
+
+	if values[metrics.ActiveConnections] != 1 || values[metrics.OpenConnections] != 1 {
+		t.Fatalf("expected one open and active connection, got %v", values)
+	}
+	if _, ok := values[metrics.RequestsInFlight]; !ok {
+		t.Fatalf("expected in-flight gauge, got %v", values)
+	}
+	if uptime := values[metrics.ServerUptime]; uptime < 90 {
+		t.Fatalf("expected uptime of at least 90s, got %v", uptime)
+	}
+}
@@ -3,10 +3,13 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"net/http"
 	"time"
 
 	"github.com/fulmenhq/gofulmen/errors"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/metrics"
 )
 
 // HealthResponse represents the aggregate health check response
@@ -28,16 +31,29 @@ type HealthChecker interface {
 	CheckHealth(ctx context.Context) error
 }
 
+// ErrCheckDisabled is returned by checkers whose subsystem is turned off in
+// configuration. Such checks report "disabled" and do not affect overall status.
+var ErrCheckDisabled = stderrors.New("check disabled by configuration")
+
 // HealthManager manages health checks and probe states
 type HealthManager struct {
 	checkers map[string]HealthChecker
+	timeouts map[string]time.Duration
+	history  *healthHistory
 	version  string
 }
 
 // NewHealthManager creates a new health manager
 func NewHealthManager(version string) *HealthManager {
+	timeouts := make(map[string]time.Duration, len(defaultProbeTimeouts))
+	for probe, timeout := range defaultProbeTimeouts {
+		timeouts[probe] = timeout
+	}
+
 	return &HealthManager{
 		checkers: make(map[string]HealthChecker),
+		timeouts: timeouts,
+		history:  newHealthHistory(DefaultHealthHistorySize),
 		version:  version,
 	}
 }
@@ -47,23 +63,76 @@ func (hm *HealthManager) RegisterChecker(name string, checker HealthChecker) {
 	hm.checkers[name] = checker
 }
 
-// runHealthChecks executes all registered health checks
-func (hm *HealthManager) runHealthChecks(ctx context.Context) map[string]string {
+// Probe names used for health metrics, error envelopes and timeout configuration
+const (
+	ProbeAggregate = "aggregate"
+	ProbeLiveness  = "live"
+	ProbeReadiness = "ready"
+	ProbeStartup   = "startup"
+)
+
+// defaultProbeTimeouts bounds check execution per probe when not configured
+var defaultProbeTimeouts = map[string]time.Duration{
+	ProbeAggregate: 5 * time.Second,
+	ProbeLiveness:  2 * time.Second,
+	ProbeReadiness: 5 * time.Second,
+	ProbeStartup:   3 * time.Second,
+}
+
+// SetProbeTimeout overrides the check timeout for a probe (non-positive values restore the default)
+func (hm *HealthManager) SetProbeTimeout(probe string, timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = defaultProbeTimeouts[probe]
+	}
+	hm.timeouts[probe] = timeout
+}
+
+// ProbeTimeout returns the check timeout for a probe
+func (hm *HealthManager) ProbeTimeout(probe string) time.Duration {
+	if timeout, ok := hm.timeouts[probe]; ok && timeout > 0 {
+		return timeout
+	}
+	return defaultProbeTimeouts[ProbeAggregate]
+}
+
+// runHealthChecks executes all registered health checks for the given probe,
+// recording per-check duration, outcome and status metrics plus health history
+func (hm *HealthManager) runHealthChecks(ctx context.Context, probe string) map[string]string {
 	checks := make(map[string]string)
+	errs := make(map[string]string)
 
 	for name, checker := range hm.checkers {
 		select {
 		case <-ctx.Done():
 			checks[name] = "timeout"
-			return checks
+			errs[name] = ctx.Err().Error()
+			metrics.RecordHealthCheckTimeout(name)
+			metrics.SetHealthStatus(name, probe, false)
+			continue
 		default:
-			if err := checker.CheckHealth(ctx); err != nil {
-				checks[name] = "unhealthy"
-			} else {
-				checks[name] = "healthy"
-			}
 		}
+
+		start := time.Now()
+		err := checker.CheckHealth(ctx)
+		if stderrors.Is(err, ErrCheckDisabled) {
+			checks[name] = "disabled"
+			continue
+		}
+		metrics.RecordHealthCheck(name, err == nil, time.Since(start))
+		metrics.SetHealthStatus(name, probe, err == nil)
+
+		if err != nil {
+			checks[name] = "unhealthy"
+			errs[name] = err.Error()
+		} else {
+			checks[name] = "healthy"
+		}
+	}
+
+	if len(errs) == 0 {
+		errs = nil
 	}
+	hm.history.record(probe, hm.determineOverallStatus(checks), checks, errs)
 
 	return checks
 }
@@ -93,10 +162,10 @@ func (hm *HealthManager) HealthHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	// Run health checks with timeout
-	checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	checkCtx, cancel := context.WithTimeout(ctx, hm.ProbeTimeout(ProbeAggregate))
 	defer cancel()
 
-	checks := hm.runHealthChecks(checkCtx)
+	checks := hm.runHealthChecks(checkCtx, ProbeAggregate)
 	status := hm.determineOverallStatus(checks)
 
 	if status == "unhealthy" {
@@ -123,16 +192,16 @@ func (hm *HealthManager) HealthHandler(w http.ResponseWriter, r *http.Request) {
 func (hm *HealthManager) LivenessHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// Run health checks with timeout for liveness (shorter timeout)
-	checkCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	// Run health checks with timeout for liveness
+	checkCtx, cancel := context.WithTimeout(ctx, hm.ProbeTimeout(ProbeLiveness))
 	defer cancel()
 
-	checks := hm.runHealthChecks(checkCtx)
+	checks := hm.runHealthChecks(checkCtx, ProbeLiveness)
 	status := hm.determineOverallStatus(checks)
 
 	if status == "unhealthy" {
 		envelope := errors.NewErrorEnvelope("SERVICE_UNAVAILABLE", "liveness probe failed")
-		envelope = enrichHealthEnvelope(envelope, "live", status, checks)
+		envelope = enrichHealthEnvelope(envelope, ProbeLiveness, status, checks)
 		respondWithError(w, r, envelope)
 		return
 	}
@@ -153,15 +222,15 @@ func (hm *HealthManager) ReadinessHandler(w http.ResponseWriter, r *http.Request
 	ctx := r.Context()
 
 	// Run health checks with timeout for readiness
-	checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	checkCtx, cancel := context.WithTimeout(ctx, hm.ProbeTimeout(ProbeReadiness))
 	defer cancel()
 
-	checks := hm.runHealthChecks(checkCtx)
+	checks := hm.runHealthChecks(checkCtx, ProbeReadiness)
 	status := hm.determineOverallStatus(checks)
 
 	if status == "unhealthy" {
 		envelope := errors.NewErrorEnvelope("SERVICE_UNAVAILABLE", "readiness probe failed")
-		envelope = enrichHealthEnvelope(envelope, "ready", status, checks)
+		envelope = enrichHealthEnvelope(envelope, ProbeReadiness, status, checks)
 		respondWithError(w, r, envelope)
 		return
 	}
@@ -182,15 +251,15 @@ func (hm *HealthManager) StartupHandler(w http.ResponseWriter, r *http.Request)
 	ctx := r.Context()
 
 	// Run health checks with timeout for startup
-	checkCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	checkCtx, cancel := context.WithTimeout(ctx, hm.ProbeTimeout(ProbeStartup))
 	defer cancel()
 
-	checks := hm.runHealthChecks(checkCtx)
+	checks := hm.runHealthChecks(checkCtx, ProbeStartup)
 	status := hm.determineOverallStatus(checks)
 
 	if status == "unhealthy" {
 		envelope := errors.NewErrorEnvelope("SERVICE_UNAVAILABLE", "startup probe failed")
-		envelope = enrichHealthEnvelope(envelope, "startup", status, checks)
+		envelope = enrichHealthEnvelope(envelope, ProbeStartup, status, checks)
 		respondWithError(w, r, envelope)
 		return
 	}
@@ -230,7 +299,7 @@ func enrichHealthEnvelope(envelope *errors.ErrorEnvelope, probe, status string,
 
 	var unhealthy []string
 	for name, result := range checks {
-		if result != "healthy" {
+		if result != "healthy" && result != "disabled" {
 			unhealthy = append(unhealthy, name)
 		}
 	}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fulmenhq/gofulmen/errors"
+	"go.uber.org/zap"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+// DefaultHealthHistorySize is the number of evaluations and transitions retained
+const DefaultHealthHistorySize = 100
+
+// HealthEvaluation records the outcome of a single probe run
+type HealthEvaluation struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Probe     string            `json:"probe"`
+	Status    string            `json:"status"`
+	Checks    map[string]string `json:"checks,omitempty"`
+	Errors    map[string]string `json:"errors,omitempty"`
+}
+
+// HealthTransition records a check changing status within a probe
+type HealthTransition struct {
+	Timestamp      time.Time `json:"timestamp"`
+	Probe          string    `json:"probe"`
+	Check          string    `json:"check"`
+	Status         string    `json:"status"`
+	PreviousStatus string    `json:"previous_status,omitempty"`
+	Error          string    `json:"error,omitempty"`
+}
+
+// HealthHistoryResponse is served by the health history endpoint (oldest first)
+type HealthHistoryResponse struct {
+	// Capacity bounds the evaluations kept per probe and the transitions kept overall
+	Capacity    int                `json:"capacity"`
+	Evaluations []HealthEvaluation `json:"evaluations"`
+	Transitions []HealthTransition `json:"transitions"`
+}
+
+// ring is a fixed-capacity buffer that overwrites its oldest entry when full
+type ring[T any] struct {
+	items []T
+	next  int
+	full  bool
+}
+
+func newRing[T any](capacity int) ring[T] {
+	return ring[T]{items: make([]T, capacity)}
+}
+
+func (r *ring[T]) add(item T) {
+	if len(r.items) == 0 {
+		return
+	}
+	r.items[r.next] = item
+	r.next = (r.next + 1) % len(r.items)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+func (r *ring[T]) snapshot() []T {
+	if !r.full {
+		return append([]T(nil), r.items[:r.next]...)
+	}
+	out := make([]T, 0, len(r.items))
+	out = append(out, r.items[r.next:]...)
+	return append(out, r.items[:r.next]...)
+}
+
+// healthHistory keeps bounded probe evaluations and per-check status
+// transitions. Each probe has its own evaluation ring, so frequent liveness
+// polls do not push readiness or startup evaluations out.
+type healthHistory struct {
+	mu          sync.Mutex
+	capacity    int
+	evaluations map[string]*ring[HealthEvaluation]
+	transitions ring[HealthTransition]
+	lastStatus  map[string]string
+}
+
+func newHealthHistory(capacity int) *healthHistory {
+	h := &healthHistory{}
+	h.reset(capacity)
+	return h
+}
+
+// reset discards recorded entries and sets the capacity (per probe for evaluations)
+func (h *healthHistory) reset(capacity int) {
+	if capacity < 0 {
+		capacity = 0
+	}
+	h.capacity = capacity
+	h.evaluations = make(map[string]*ring[HealthEvaluation])
+	h.transitions = newRing[HealthTransition](capacity)
+	h.lastStatus = make(map[string]string)
+}
+
+// record stores an evaluation and any check transitions it caused
+func (h *healthHistory) record(probe, status string, checks, errs map[string]string) {
+	now := time.Now().UTC()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	evaluations, ok := h.evaluations[probe]
+	if !ok {
+		r := newRing[HealthEvaluation](h.capacity)
+		evaluations = &r
+		h.evaluations[probe] = evaluations
+	}
+	evaluations.add(HealthEvaluation{
+		Timestamp: now,
+		Probe:     probe,
+		Status:    status,
+		Checks:    checks,
+		Errors:    errs,
+	})
+
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		current := checks[name]
+		key := probe + "/" + name
+		previous, seen := h.lastStatus[key]
+		h.lastStatus[key] = current
+
+		// The first healthy observation is not interesting; a first failure is
+		if previous == current || (!seen && current == "healthy") {
+			continue
+		}
+
+		transition := HealthTransition{
+			Timestamp:      now,
+			Probe:          probe,
+			Check:          name,
+			Status:         current,
+			PreviousStatus: previous,
+			Error:          errs[name],
+		}
+		h.transitions.add(transition)
+		logTransition(transition)
+	}
+}
+
+func (h *healthHistory) snapshot() HealthHistoryResponse {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	evaluations := []HealthEvaluation{}
+	for _, r := range h.evaluations {
+		evaluations = append(evaluations, r.snapshot()...)
+	}
+	sort.SliceStable(evaluations, func(i, j int) bool {
+		return evaluations[i].Timestamp.Before(evaluations[j].Timestamp)
+	})
+
+	return HealthHistoryResponse{
+		Capacity:    h.capacity,
+		Evaluations: evaluations,
+		Transitions: h.transitions.snapshot(),
+	}
+}
+
+// logTransition surfaces check status changes in the server log
+func logTransition(t HealthTransition) {
+	if observability.ServerLogger == nil {
+		return
+	}
+	logger := observability.ServerLogger.Named("health")
+
+	fields := []zap.Field{
+		zap.String("probe", t.Probe),
+		zap.String("check", t.Check),
+		zap.String("status", t.Status),
+		zap.String("previous_status", t.PreviousStatus),
+	}
+	if t.Error != "" {
+		fields = append(fields, zap.String("error", t.Error))
+	}
+
+	if t.Status == "healthy" {
+		logger.Info("Health check recovered", fields...)
+		return
+	}
+	logger.Warn("Health check failing", fields...)
+}
+
+// SetHistorySize resizes the health history buffers (evaluations are kept
+// per probe), discarding existing entries
+func (hm *HealthManager) SetHistorySize(size int) {
+	hm.history.mu.Lock()
+	defer hm.history.mu.Unlock()
+	hm.history.reset(size)
+}
+
+// History returns a snapshot of recorded evaluations and transitions
+func (hm *HealthManager) History() HealthHistoryResponse {
+	return hm.history.snapshot()
+}
+
+// HistoryHandler serves the recorded health history as JSON
+func (hm *HealthManager) HistoryHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(hm.History())
+}
+
+// HealthHistoryHandler is the backward-compatible handler that uses the global manager
+func HealthHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	if globalHealthManager != nil {
+		globalHealthManager.HistoryHandler(w, r)
+		return
+	}
+
+	envelope := errors.NewErrorEnvelope("SERVICE_UNAVAILABLE", "health manager not initialized")
+	envelope = enrichHealthEnvelope(envelope, "history", "unknown", nil)
+	respondWithError(w, r, envelope)
+}
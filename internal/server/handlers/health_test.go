@@ -7,6 +7,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/fulmenhq/gofulmen/telemetry"
+	telemetrytesting "github.com/fulmenhq/gofulmen/telemetry/testing"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/metrics"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
 )
 
 type stubChecker struct {
@@ -102,3 +109,209 @@ func TestDetermineOverallStatusTreatsTimeoutAsDegraded(t *testing.T) {
 		t.Fatalf("expected degraded status, got %s", status)
 	}
 }
+
+func TestDisabledChecksDoNotAffectStatus(t *testing.T) {
+	manager := NewHealthManager("dev")
+	manager.RegisterChecker("ok", stubChecker{})
+	manager.RegisterChecker("telemetry", stubChecker{err: ErrCheckDisabled})
+
+	rec := httptest.NewRecorder()
+	manager.HealthHandler(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var resp HealthResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Status != "healthy" {
+		t.Fatalf("expected healthy status, got %s", resp.Status)
+	}
+	if resp.Checks["telemetry"] != "disabled" {
+		t.Fatalf("expected telemetry check to be disabled, got %s", resp.Checks["telemetry"])
+	}
+}
+
+func TestHealthHandlerEmitsCheckMetrics(t *testing.T) {
+	collector := telemetrytesting.NewFakeCollector()
+	sys, err := telemetry.NewSystem(&telemetry.Config{Enabled: true, Emitter: collector})
+	if err != nil {
+		t.Fatalf("failed to create telemetry system: %v", err)
+	}
+	original := observability.TelemetrySystem
+	observability.TelemetrySystem = sys
+	t.Cleanup(func() { observability.TelemetrySystem = original })
+
+	manager := NewHealthManager("dev")
+	manager.RegisterChecker("ok", stubChecker{err: nil})
+	manager.RegisterChecker("db", stubChecker{err: errors.New("down")})
+
+	req := httptest.NewRequest(http.MethodGet, "/health/ready", nil)
+	rec := httptest.NewRecorder()
+	manager.ReadinessHandler(rec, req)
+
+	if got := collector.CountMetricsByName(metrics.HealthCheckTotal); got != 2 {
+		t.Fatalf("expected 2 %s samples, got %d", metrics.HealthCheckTotal, got)
+	}
+	if got := collector.CountMetricsByName(metrics.HealthCheckDuration); got != 2 {
+		t.Fatalf("expected 2 %s samples, got %d", metrics.HealthCheckDuration, got)
+	}
+
+	statuses := map[string]float64{}
+	for _, m := range collector.GetMetricsByName(metrics.HealthStatus) {
+		if m.Tags["probe"] != "ready" {
+			t.Fatalf("expected probe label ready, got %q", m.Tags["probe"])
+		}
+		value, _ := m.Value.(float64)
+		statuses[m.Tags["check"]] = value
+	}
+	if statuses["ok"] != 1 || statuses["db"] != 0 {
+		t.Fatalf("unexpected health status gauges: %v", statuses)
+	}
+}
+
+func TestTimedOutChecksAreCounted(t *testing.T) {
+	collector := telemetrytesting.NewFakeCollector()
+	sys, err := telemetry.NewSystem(&telemetry.Config{Enabled: true, Emitter: collector})
+	if err != nil {
+		t.Fatalf("failed to create telemetry system: %v", err)
+	}
+	original := observability.TelemetrySystem
+	observability.TelemetrySystem = sys
+	t.Cleanup(func() { observability.TelemetrySystem = original })
+
+	manager := NewHealthManager("dev")
+	manager.RegisterChecker("db", stubChecker{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if checks := manager.runHealthChecks(ctx, ProbeReadiness); checks["db"] != "timeout" {
+		t.Fatalf("expected db check to time out, got %q", checks["db"])
+	}
+
+	samples := collector.GetMetricsByName(metrics.HealthCheckTotal)
+	if len(samples) != 1 || samples[0].Tags["status"] != "timeout" || samples[0].Tags["check"] != "db" {
+		t.Fatalf("expected one timeout sample for db, got %+v", samples)
+	}
+}
+
+func TestProbeTimeoutsAreConfigurable(t *testing.T) {
+	manager := NewHealthManager("dev")
+
+	if got := manager.ProbeTimeout(ProbeLiveness); got != 2*time.Second {
+		t.Fatalf("expected default liveness timeout 2s, got %s", got)
+	}
+
+	manager.SetProbeTimeout(ProbeLiveness, 750*time.Millisecond)
+	if got := manager.ProbeTimeout(ProbeLiveness); got != 750*time.Millisecond {
+		t.Fatalf("expected configured liveness timeout 750ms, got %s", got)
+	}
+
+	manager.SetProbeTimeout(ProbeLiveness, 0)
+	if got := manager.ProbeTimeout(ProbeLiveness); got != 2*time.Second {
+		t.Fatalf("expected zero timeout to restore default, got %s", got)
+	}
+}
+
+func TestHealthHistoryRecordsEvaluationsAndTransitions(t *testing.T) {
+	manager := NewHealthManager("dev")
+	manager.SetHistorySize(3)
+
+	flaky := &toggleChecker{}
+	manager.RegisterChecker("flaky", flaky)
+
+	run := func() {
+		rec := httptest.NewRecorder()
+		manager.ReadinessHandler(rec, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+	}
+
+	run()                          // healthy: no transition
+	flaky.err = errors.New("boom") // healthy -> unhealthy
+	run()
+	flaky.err = nil // unhealthy -> healthy
+	run()
+	run()
+
+	history := manager.History()
+	if history.Capacity != 3 {
+		t.Fatalf("expected capacity 3, got %d", history.Capacity)
+	}
+	if len(history.Evaluations) != 3 {
+		t.Fatalf("expected ring buffer to retain 3 evaluations, got %d", len(history.Evaluations))
+	}
+	if history.Evaluations[0].Status != "unhealthy" || history.Evaluations[0].Errors["flaky"] != "boom" {
+		t.Fatalf("expected oldest retained evaluation to be the failure, got %+v", history.Evaluations[0])
+	}
+
+	if len(history.Transitions) != 2 {
+		t.Fatalf("expected 2 transitions, got %+v", history.Transitions)
+	}
+	failed := history.Transitions[0]
+	if failed.Probe != ProbeReadiness || failed.Check != "flaky" || failed.Status != "unhealthy" ||
+		failed.PreviousStatus != "healthy" || failed.Error != "boom" {
+		t.Fatalf("unexpected failure transition: %+v", failed)
+	}
+	if history.Transitions[1].Status != "healthy" {
+		t.Fatalf("expected recovery transition, got %+v", history.Transitions[1])
+	}
+
+	rec := httptest.NewRecorder()
+	manager.HistoryHandler(rec, httptest.NewRequest(http.MethodGet, "/health/history", nil))
+	var resp HealthHistoryResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode history response: %v", err)
+	}
+	if len(resp.Transitions) != 2 {
+		t.Fatalf("expected history endpoint to return 2 transitions, got %d", len(resp.Transitions))
+	}
+}
+
+func TestHealthHistoryKeepsEvaluationsPerProbe(t *testing.T) {
+	manager := NewHealthManager("dev")
+	manager.SetHistorySize(2)
+	manager.RegisterChecker("ok", stubChecker{})
+
+	manager.ReadinessHandler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+	for i := 0; i < 5; i++ {
+		manager.LivenessHandler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health/live", nil))
+	}
+
+	perProbe := map[string]int{}
+	for _, evaluation := range manager.History().Evaluations {
+		perProbe[evaluation.Probe]++
+	}
+	if perProbe[ProbeLiveness] != 2 || perProbe[ProbeReadiness] != 1 {
+		t.Fatalf("expected 2 liveness and 1 readiness evaluations, got %v", perProbe)
+	}
+}
+
+func TestSetHistorySizeWhileRecording(t *testing.T) {
+	manager := NewHealthManager("dev")
+	manager.RegisterChecker("ok", stubChecker{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			manager.LivenessHandler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health/live", nil))
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		manager.SetHistorySize(i % 5)
+	}
+	<-done
+
+	if got := manager.History().Capacity; got != 4 {
+		t.Fatalf("expected capacity 4, got %d", got)
+	}
+}
+
+type toggleChecker struct {
+	err error
+}
+
+func (c *toggleChecker) CheckHealth(ctx context.Context) error {
+	return c.err
+}
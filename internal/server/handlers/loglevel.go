@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/fulmenhq/gofulmen/errors"
+	"go.uber.org/zap"
+
+	apperrors "github.com/fulmenhq/forge-workhorse-groningen/internal/errors"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+// MaxLogLevelTTL caps temporary overrides so a forgotten debug level reverts
+const MaxLogLevelTTL = 24 * time.Hour
+
+// componentPattern matches logger names such as "http" or "metrics.push"
+var componentPattern = regexp.MustCompile(`^[a-z0-9_-]+(\.[a-z0-9_-]+)*$`)
+
+// LogLevelRequest is the body of PUT /admin/loglevel
+type LogLevelRequest struct {
+	// Level is trace, debug, info, warn or error; "inherit" removes a
+	// component override
+	Level string `json:"level"`
+
+	// Component is a logger name (http, health, metrics.push, ...); empty
+	// changes the global level
+	Component string `json:"component,omitempty"`
+
+	// TTL reverts the change after this duration (e.g. "15m"); empty keeps it
+	TTL string `json:"ttl,omitempty"`
+}
+
+// LogLevelHandler serves the server logger's global level and component
+// overrides as JSON
+func LogLevelHandler(w http.ResponseWriter, r *http.Request) {
+	logger := observability.ServerLogger
+	if logger == nil {
+		respondWithError(w, r, errors.NewErrorEnvelope("SERVICE_UNAVAILABLE", "server logger not initialized"))
+		return
+	}
+
+	writeLogLevels(w, logger)
+}
+
+// SetLogLevelHandler changes the global or a component log level at runtime,
+// optionally for a limited time, and responds with the resulting levels
+func SetLogLevelHandler(w http.ResponseWriter, r *http.Request) {
+	logger := observability.ServerLogger
+	if logger == nil {
+		respondWithError(w, r, errors.NewErrorEnvelope("SERVICE_UNAVAILABLE", "server logger not initialized"))
+		return
+	}
+
+	var req LogLevelRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+		respondWithError(w, r, apperrors.WrapInvalidInput(r.Context(), err, "invalid log level request body"))
+		return
+	}
+
+	if req.Component != "" && !componentPattern.MatchString(req.Component) {
+		respondWithError(w, r, apperrors.NewInvalidInputError("component must be a logger name such as http or metrics.push"))
+		return
+	}
+
+	var ttl time.Duration
+	if req.TTL != "" {
+		parsed, err := time.ParseDuration(req.TTL)
+		if err != nil || parsed <= 0 || parsed > MaxLogLevelTTL {
+			respondWithError(w, r, apperrors.NewInvalidInputError("ttl must be a positive duration up to 24h, e.g. 15m"))
+			return
+		}
+		ttl = parsed
+	}
+
+	if req.Level == observability.InheritLevel {
+		if req.Component == "" {
+			respondWithError(w, r, apperrors.NewInvalidInputError("level inherit requires a component"))
+			return
+		}
+		logger.ResetComponentLevel(req.Component)
+	} else {
+		severity, err := observability.ParseLevel(req.Level)
+		if err != nil {
+			respondWithError(w, r, apperrors.NewInvalidInputError(err.Error()))
+			return
+		}
+		logger.SetComponentLevel(req.Component, severity, ttl)
+	}
+
+	// Warn so the change is visible at the default level
+	observability.FromContext(r.Context()).Warn("Log level changed",
+		zap.String("component", req.Component),
+		zap.String("level", req.Level),
+		zap.Duration("ttl", ttl))
+
+	writeLogLevels(w, logger)
+}
+
+func writeLogLevels(w http.ResponseWriter, logger *observability.Logger) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(logger.Levels())
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+func setupLogLevelLogger(t *testing.T) *observability.Logger {
+	t.Helper()
+	logger, err := observability.NewLogger("test", config.LoggingConfig{
+		Level: "info",
+		Sinks: []config.LogSinkConfig{{Type: "file", Path: t.TempDir() + "/server.log"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	original := observability.ServerLogger
+	observability.ServerLogger = logger
+	t.Cleanup(func() {
+		observability.ServerLogger = original
+		_ = logger.ReopenFiles()
+	})
+	return logger
+}
+
+func putLogLevel(body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPut, "/admin/loglevel", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	SetLogLevelHandler(rec, req)
+	return rec
+}
+
+func TestSetLogLevelHandler(t *testing.T) {
+	logger := setupLogLevelLogger(t)
+
+	rec := putLogLevel(`{"level":"debug","component":"http","ttl":"15m"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var status observability.LevelStatus
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	override := status.Components["http"]
+	if status.Level != "INFO" || override.Level != "DEBUG" || override.ExpiresAt == nil || override.RevertsTo != "inherit" {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+
+	rec = putLogLevel(`{"level":"warn"}`)
+	if rec.Code != http.StatusOK || logger.GetLevel() != "WARN" {
+		t.Fatalf("expected global level WARN, got %d %s", rec.Code, logger.GetLevel())
+	}
+
+	rec = putLogLevel(`{"level":"inherit","component":"http"}`)
+	if rec.Code != http.StatusOK || len(logger.Levels().Components) != 0 {
+		t.Fatalf("expected the http override to be removed: %+v", logger.Levels())
+	}
+
+	get := httptest.NewRecorder()
+	LogLevelHandler(get, httptest.NewRequest(http.MethodGet, "/admin/loglevel", nil))
+	if get.Code != http.StatusOK || !strings.Contains(get.Body.String(), `"level":"WARN"`) {
+		t.Fatalf("unexpected GET response: %d %s", get.Code, get.Body.String())
+	}
+}
+
+func TestSetLogLevelHandlerRejectsInvalidRequests(t *testing.T) {
+	setupLogLevelLogger(t)
+
+	for _, body := range []string{
+		`not json`,
+		`{"level":"verbose"}`,
+		`{"level":"inherit"}`,
+		`{"level":"debug","component":"HTTP Server"}`,
+		`{"level":"debug","ttl":"soon"}`,
+		`{"level":"debug","ttl":"-1m"}`,
+		`{"level":"debug","ttl":"48h"}`,
+	} {
+		if rec := putLogLevel(body); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", body, rec.Code)
+		}
+	}
+}
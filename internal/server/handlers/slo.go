@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/fulmenhq/gofulmen/errors"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/slo"
+)
+
+// SLOHandler serves the current SLO evaluation (SLI, error budget, burn
+// rates and alerts per objective) as JSON
+func SLOHandler(w http.ResponseWriter, r *http.Request) {
+	tracker := slo.Get()
+	if tracker == nil {
+		respondWithError(w, r, errors.NewErrorEnvelope("NOT_FOUND", "No SLOs configured (slo.objectives is empty)"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(tracker.Report())
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/slo"
+)
+
+func TestSLOHandlerReportsObjectives(t *testing.T) {
+	if _, err := slo.Init(config.SLOConfig{Objectives: []config.SLOObjectiveConfig{
+		{Name: "api", Type: slo.TypeAvailability, Target: 99.9},
+	}}); err != nil {
+		t.Fatalf("failed to init SLOs: %v", err)
+	}
+	t.Cleanup(func() { _, _ = slo.Init(config.SLOConfig{}) })
+
+	slo.Record("/items", http.StatusOK, time.Millisecond)
+	slo.Record("/items", http.StatusBadGateway, time.Millisecond)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/slo", nil)
+	rec := httptest.NewRecorder()
+
+	SLOHandler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var report slo.Report
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(report.Objectives) != 1 {
+		t.Fatalf("expected one objective, got %d", len(report.Objectives))
+	}
+	got := report.Objectives[0]
+	if got.Name != "api" || got.Total != 2 || got.Good != 1 || got.Window != "30d" {
+		t.Fatalf("unexpected objective report: %+v", got)
+	}
+	if !got.Alerts.Page {
+		t.Fatalf("expected a 50%% error rate to page, got %+v", got.Alerts)
+	}
+}
+
+func TestSLOHandlerNotFoundWithoutObjectives(t *testing.T) {
+	_, _ = slo.Init(config.SLOConfig{})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/slo", nil)
+	rec := httptest.NewRecorder()
+
+	SLOHandler(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", rec.Code)
+	}
+}
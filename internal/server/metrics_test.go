@@ -2,45 +2,41 @@ package server
 
 import (
 	"encoding/json"
-	"io"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"go.uber.org/zap"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
 	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
-	"github.com/fulmenhq/gofulmen/telemetry/exporters"
 )
 
-type roundTripFunc func(*http.Request) (*http.Response, error)
-
-func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
-	return f(r)
-}
-
-func TestMetricsHandlerProxiesPrometheusOutput(t *testing.T) {
-	originalClient := metricsProxyClient
+func TestMetricsHandlerRendersInProcess(t *testing.T) {
+	if err := observability.InitMetrics("test", 0, "test"); err != nil {
+		t.Fatalf("failed to init metrics: %v", err)
+	}
 	t.Cleanup(func() {
-		metricsProxyClient = originalClient
+		observability.MetricsRegistry = nil
+		observability.TelemetrySystem = nil
 	})
 
-	metricsProxyClient = &http.Client{
-		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
-			body := "# HELP http_requests_total Total number of HTTP requests\nhttp_requests_total 1\n"
-			resp := &http.Response{
-				StatusCode: http.StatusOK,
-				Body:       io.NopCloser(strings.NewReader(body)),
-				Header:     make(http.Header),
-			}
-			resp.Header.Set("Content-Type", "text/plain; version=0.0.4")
-			return resp, nil
-		}),
+	if port := observability.GetMetricsPort(); port != 0 {
+		t.Fatalf("expected no dedicated listener for port 0, got %d", port)
 	}
 
-	observability.PrometheusExporter = exporters.NewPrometheusExporter("test", ":9090")
-	t.Cleanup(func() {
-		observability.PrometheusExporter = nil
-	})
+	exporter := observability.MetricsRegistry
+	tags := map[string]string{"method": "GET", "status": "200"}
+	_ = exporter.Counter("http_requests_total", 1, tags)
+	_ = exporter.Counter("http_requests_total", 2, tags)
+	_ = exporter.Gauge("active_workers", 3, nil)
+	_ = exporter.Gauge("active_workers", 5, nil)
+	_ = exporter.Histogram("job_duration_ms", 20*time.Millisecond, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
 	rec := httptest.NewRecorder()
@@ -57,13 +53,58 @@ func TestMetricsHandlerProxiesPrometheusOutput(t *testing.T) {
 	}
 
 	body := rec.Body.String()
-	if !strings.Contains(body, "http_requests_total") {
-		t.Fatalf("expected Prometheus output to include metric name, got: %s", body)
+	for _, want := range []string{
+		"# TYPE test_http_requests_total counter\n",
+		`test_http_requests_total{method="GET",status="200"} 3` + "\n",
+		"test_active_workers 5\n",
+		"# TYPE test_job_duration_ms histogram\n",
+		`test_job_duration_ms_bucket{le="10"} 0` + "\n",
+		`test_job_duration_ms_bucket{le="50"} 1` + "\n",
+		`test_job_duration_ms_bucket{le="+Inf"} 1` + "\n",
+		"test_job_duration_ms_sum 20\n",
+		"test_job_duration_ms_count 1\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected Prometheus output to contain %q, got:\n%s", want, body)
+		}
+	}
+
+	// Repeated events must collapse into a single series
+	if n := strings.Count(body, "test_http_requests_total{"); n != 1 {
+		t.Fatalf("expected one counter series, got %d:\n%s", n, body)
+	}
+}
+
+func TestMetricsHandlerServesOpenMetricsWhenAccepted(t *testing.T) {
+	if err := observability.InitMetrics("test", 0, "test"); err != nil {
+		t.Fatalf("failed to init metrics: %v", err)
+	}
+	t.Cleanup(func() {
+		observability.MetricsRegistry = nil
+		observability.TelemetrySystem = nil
+	})
+
+	_ = observability.TelemetrySystem.Counter("http_requests_total", 1, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text;version=1.0.0,text/plain;version=0.0.4;q=0.5")
+	rec := httptest.NewRecorder()
+
+	MetricsHandler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if contentType := rec.Header().Get("Content-Type"); contentType != observability.OpenMetricsContentType {
+		t.Fatalf("expected OpenMetrics content type, got %s", contentType)
+	}
+	if body := rec.Body.String(); !strings.HasSuffix(body, "# EOF\n") {
+		t.Fatalf("expected OpenMetrics output, got:\n%s", body)
 	}
 }
 
 func TestMetricsHandlerReturnsServiceUnavailableWithoutExporter(t *testing.T) {
-	observability.PrometheusExporter = nil
+	observability.MetricsRegistry = nil
 
 	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
 	rec := httptest.NewRecorder()
@@ -88,3 +129,77 @@ func TestMetricsHandlerReturnsServiceUnavailableWithoutExporter(t *testing.T) {
 		t.Fatalf("expected error code SERVICE_UNAVAILABLE, got %s", resp.Error.Code)
 	}
 }
+
+func TestMetricsHandlerReturnsNotFoundWhenDisabled(t *testing.T) {
+	observability.DisableMetrics()
+	t.Cleanup(func() {
+		// InitMetrics clears the disabled flag; drop the globals it creates
+		_ = observability.InitMetrics("test", 0, "test")
+		observability.MetricsRegistry = nil
+		observability.TelemetrySystem = nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+
+	MetricsHandler(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", rec.Code)
+	}
+
+	var resp struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Error.Code != "NOT_FOUND" {
+		t.Fatalf("expected error code NOT_FOUND, got %s", resp.Error.Code)
+	}
+}
+
+// brokenWriter fails every body write, like a client that hung up
+type brokenWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (brokenWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestMetricsHandlerLogsWriteErrorsWithRequestLogger(t *testing.T) {
+	if err := observability.InitMetrics("test", 0, "test"); err != nil {
+		t.Fatalf("failed to init metrics: %v", err)
+	}
+	t.Cleanup(func() {
+		observability.MetricsRegistry = nil
+		observability.TelemetrySystem = nil
+	})
+
+	_ = observability.TelemetrySystem.Counter("http_requests_total", 1, nil)
+
+	path := filepath.Join(t.TempDir(), "server.log")
+	logger, err := observability.NewLogger("test", config.LoggingConfig{
+		Level: "info",
+		Sinks: []config.LogSinkConfig{{Type: "file", Path: path}},
+	})
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req = req.WithContext(observability.WithLogger(req.Context(), logger.With(zap.String("request_id", "req-1"))))
+
+	MetricsHandler(brokenWriter{httptest.NewRecorder()}, req)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "Failed to write metrics response") || !strings.Contains(string(data), `"request_id":"req-1"`) {
+		t.Fatalf("expected the write error on the request logger, got:\n%s", data)
+	}
+}
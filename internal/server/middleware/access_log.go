@@ -0,0 +1,232 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+// AccessLogComponent names access log entries. It sits under LoggerComponent,
+// so /admin/loglevel can quiet access entries without hiding handler logs.
+const AccessLogComponent = LoggerComponent + ".access"
+
+// Access log formats
+const (
+	AccessLogJSON     = "json"
+	AccessLogCommon   = "common"
+	AccessLogCombined = "combined"
+)
+
+// clfTimeFormat is the Common Log Format timestamp
+const clfTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// accessOutput is where common and combined lines go without a path; tests
+// swap it for a buffer
+var accessOutput io.Writer = os.Stdout
+
+// AccessLogger writes one entry per request, after the handler returns. It
+// skips excluded paths and samples successful requests; client and server
+// errors and slow requests are always logged.
+type AccessLogger struct {
+	cfg      config.AccessLogConfig
+	exact    map[string]bool
+	prefixes []string
+
+	// sample reports whether a successful request is logged
+	sample func() bool
+
+	// Common and combined lines bypass the server logger
+	mu   sync.Mutex
+	out  io.Writer
+	file *lumberjack.Logger
+}
+
+// NewAccessLogger validates cfg and opens the output for common and combined
+// formats. A disabled access logger passes requests through.
+func NewAccessLogger(cfg config.AccessLogConfig) (*AccessLogger, error) {
+	if cfg.SampleRate < 0 || cfg.SampleRate > 1 {
+		return nil, fmt.Errorf("sample_rate must be between 0 and 1, got %g", cfg.SampleRate)
+	}
+	if cfg.SlowThreshold < 0 {
+		return nil, fmt.Errorf("slow_threshold must not be negative")
+	}
+
+	a := &AccessLogger{cfg: cfg, exact: make(map[string]bool)}
+	for _, path := range cfg.ExcludePaths {
+		if prefix, ok := strings.CutSuffix(path, "*"); ok {
+			a.prefixes = append(a.prefixes, prefix)
+		} else {
+			a.exact[path] = true
+		}
+	}
+
+	rate := cfg.SampleRate
+	a.sample = func() bool { return rate >= 1 || rand.Float64() < rate }
+
+	switch cfg.Format {
+	case "", AccessLogJSON:
+		if cfg.Path != "" {
+			return nil, fmt.Errorf("path is only used with the common and combined formats; json entries go to logging.sinks")
+		}
+	case AccessLogCommon, AccessLogCombined:
+		a.out = accessOutput
+		if cfg.Path != "" && cfg.Enabled {
+			file, err := observability.OpenLogFile(cfg.Path)
+			if err != nil {
+				return nil, err
+			}
+			a.out, a.file = file, file
+		}
+	default:
+		return nil, fmt.Errorf("unknown format %q (want json, common or combined)", cfg.Format)
+	}
+	return a, nil
+}
+
+// ReopenFiles closes the access log file so the next line reopens it, for
+// logrotate
+func (a *AccessLogger) ReopenFiles() error {
+	if a == nil || a.file == nil {
+		return nil
+	}
+	return a.file.Close()
+}
+
+// Middleware logs each request once it completes. Mount it after
+// RequestLogger so JSON entries carry the request fields.
+func (a *AccessLogger) Middleware(next http.Handler) http.Handler {
+	if a == nil || !a.cfg.Enabled {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.excluded(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		var body *countingBody
+		if r.Body != nil && r.Body != http.NoBody {
+			body = &countingBody{ReadCloser: r.Body}
+			r.Body = body
+		}
+
+		next.ServeHTTP(wrapped, r)
+
+		duration := time.Since(start)
+		slow := a.cfg.SlowThreshold > 0 && duration > a.cfg.SlowThreshold
+		if wrapped.statusCode < http.StatusBadRequest && !slow && !a.sample() {
+			return
+		}
+
+		bodySize := int64(0)
+		if body != nil {
+			bodySize = body.bytesRead
+		}
+
+		switch a.cfg.Format {
+		case AccessLogCommon, AccessLogCombined:
+			a.writeLine(r, start, wrapped)
+		default:
+			a.logEntry(r, wrapped, duration, bodySize, slow)
+		}
+	})
+}
+
+func (a *AccessLogger) excluded(path string) bool {
+	if a.exact[path] {
+		return true
+	}
+	for _, prefix := range a.prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// logEntry writes a structured entry through the request logger, which
+// carries request_id, method, route, client_ip and trace_id
+func (a *AccessLogger) logEntry(r *http.Request, rw *responseWriter, duration time.Duration, bodySize int64, slow bool) {
+	fields := []zap.Field{
+		zap.String("path", r.URL.Path),
+		zap.Int("status", rw.statusCode),
+		zap.Duration("duration", duration),
+		zap.Int64("request_size", bodySize),
+		zap.Int64("response_size", rw.bytesWritten),
+	}
+	if a.cfg.SampleRate < 1 && rw.statusCode < http.StatusBadRequest && !slow {
+		// Lets log queries scale sampled counts back up
+		fields = append(fields, zap.Float64("sample_rate", a.cfg.SampleRate))
+	}
+
+	logger := observability.FromContext(r.Context()).Named("access")
+	if slow {
+		fields = append(fields, zap.Duration("slow_threshold", a.cfg.SlowThreshold))
+		logger.Warn("HTTP request completed", fields...)
+		return
+	}
+	logger.Info("HTTP request completed", fields...)
+}
+
+// writeLine writes a Common or Combined Log Format line:
+//
+//	host ident authuser [date] "request" status bytes ["referer" "user-agent"]
+//
+// The user, request line and referer are redacted like log entries, since
+// query strings often carry tokens.
+func (a *AccessLogger) writeLine(r *http.Request, start time.Time, rw *responseWriter) {
+	user := "-"
+	if name, _, ok := r.BasicAuth(); ok && name != "" {
+		user = name
+	} else if r.URL.User != nil && r.URL.User.Username() != "" {
+		user = r.URL.User.Username()
+	}
+	size := "-"
+	if rw.bytesWritten > 0 {
+		size = strconv.FormatInt(rw.bytesWritten, 10)
+	}
+	redactor := observability.CurrentRedactor()
+	requestLine := redactor.String(r.Method + " " + r.RequestURI + " " + r.Proto)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s - %s [%s] \"%s\" %d %s",
+		clientIP(r), clfEscape(redactor.String(user)), start.Format(clfTimeFormat), clfEscape(requestLine), rw.statusCode, size)
+	if a.cfg.Format == AccessLogCombined {
+		fmt.Fprintf(&b, " \"%s\" \"%s\"", clfField(redactor.String(r.Referer())), clfField(r.UserAgent()))
+	}
+	b.WriteByte('\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, _ = io.WriteString(a.out, b.String())
+}
+
+// clfField escapes a quoted field, using "-" for empty values
+func clfField(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return clfEscape(s)
+}
+
+// clfEscape escapes quotes, backslashes and control characters so a
+// client cannot break or forge lines
+func clfEscape(s string) string {
+	quoted := strconv.Quote(s)
+	return quoted[1 : len(quoted)-1]
+}
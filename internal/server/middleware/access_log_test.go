@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+)
+
+// accessRouter mounts the access log in the server's order, with handlers
+// for each status the tests need
+func accessRouter(t *testing.T, cfg config.AccessLogConfig) *chi.Mux {
+	t.Helper()
+	accessLog, err := NewAccessLogger(cfg)
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Use(RequestID, RequestLogger, accessLog.Middleware)
+	r.Get("/ok", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	})
+	r.Get("/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	r.Get("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+	})
+	r.Get("/health/live", func(w http.ResponseWriter, r *http.Request) {})
+	r.Get("/metrics", func(w http.ResponseWriter, r *http.Request) {})
+	return r
+}
+
+func serve(r http.Handler, target string) {
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+}
+
+func TestAccessLog_ExcludesPaths(t *testing.T) {
+	readEntries := setupFileLogger(t)
+	r := accessRouter(t, config.AccessLogConfig{
+		Enabled:      true,
+		SampleRate:   1,
+		ExcludePaths: []string{"/health/*", "/metrics"},
+	})
+
+	serve(r, "/health/live")
+	serve(r, "/metrics")
+	serve(r, "/ok")
+
+	entries := readEntries()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "/ok", entries[0]["path"])
+	assert.Equal(t, "INFO", entries[0]["severity"])
+	assert.EqualValues(t, 5, entries[0]["response_size"])
+	assert.NotContains(t, entries[0], "sample_rate")
+}
+
+func TestAccessLog_SamplesOnlySuccesses(t *testing.T) {
+	readEntries := setupFileLogger(t)
+	r := accessRouter(t, config.AccessLogConfig{Enabled: true, SampleRate: 0})
+
+	for range 10 {
+		serve(r, "/ok")
+	}
+	serve(r, "/missing")
+
+	entries := readEntries()
+	require.Len(t, entries, 1, "4xx responses are never sampled out")
+	assert.EqualValues(t, http.StatusNotFound, entries[0]["status"])
+}
+
+func TestAccessLog_RecordsSampleRate(t *testing.T) {
+	readEntries := setupFileLogger(t)
+	accessLog, err := NewAccessLogger(config.AccessLogConfig{Enabled: true, SampleRate: 0.25})
+	require.NoError(t, err)
+	accessLog.sample = func() bool { return true }
+
+	r := chi.NewRouter()
+	r.Use(RequestID, RequestLogger, accessLog.Middleware)
+	r.Get("/ok", func(w http.ResponseWriter, r *http.Request) {})
+	serve(r, "/ok")
+
+	entries := readEntries()
+	require.Len(t, entries, 1)
+	assert.Equal(t, 0.25, entries[0]["sample_rate"])
+}
+
+func TestAccessLog_PromotesSlowRequests(t *testing.T) {
+	readEntries := setupFileLogger(t)
+	r := accessRouter(t, config.AccessLogConfig{Enabled: true, SampleRate: 0, SlowThreshold: 5 * time.Millisecond})
+
+	serve(r, "/ok")
+	serve(r, "/slow")
+
+	entries := readEntries()
+	require.Len(t, entries, 1, "slow requests bypass sampling")
+	assert.Equal(t, "/slow", entries[0]["path"])
+	assert.Equal(t, "WARN", entries[0]["severity"])
+	assert.Equal(t, "5ms", entries[0]["slow_threshold"])
+}
+
+func TestAccessLog_Disabled(t *testing.T) {
+	readEntries := setupFileLogger(t)
+	r := accessRouter(t, config.AccessLogConfig{SampleRate: 1})
+
+	serve(r, "/missing")
+
+	assert.Empty(t, readEntries())
+}
+
+func TestAccessLog_CommonAndCombinedFormats(t *testing.T) {
+	var out bytes.Buffer
+	original := accessOutput
+	accessOutput = &out
+	t.Cleanup(func() { accessOutput = original })
+
+	common := accessRouter(t, config.AccessLogConfig{Enabled: true, SampleRate: 1, Format: AccessLogCommon})
+	combined := accessRouter(t, config.AccessLogConfig{Enabled: true, SampleRate: 1, Format: AccessLogCombined})
+
+	req := httptest.NewRequest(http.MethodGet, "/ok?access_token=abc123secret", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	req.SetBasicAuth("jane", "pw")
+	common.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodGet, "/missing", nil)
+	req.RemoteAddr = "203.0.113.8:51234"
+	req.Header.Set("Referer", "https://example.com/")
+	req.Header.Set("User-Agent", `curl/8.0 "quoted"`)
+	combined.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+
+	date := `\[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\]`
+	assert.Regexp(t, regexp.MustCompile(`^203\.0\.113\.7 - jane `+date+` "GET /ok\?access_token=\[REDACTED\] HTTP/1\.1" 200 5$`), lines[0])
+	assert.Regexp(t, regexp.MustCompile(`^203\.0\.113\.8 - - `+date+` "GET /missing HTTP/1\.1" 404 - "https://example\.com/" "curl/8\.0 \\"quoted\\""$`), lines[1])
+}
+
+func TestNewAccessLogger_RejectsInvalidConfig(t *testing.T) {
+	for name, cfg := range map[string]config.AccessLogConfig{
+		"sample rate":    {SampleRate: 1.5},
+		"slow threshold": {SampleRate: 1, SlowThreshold: -time.Second},
+		"format":         {SampleRate: 1, Format: "apache"},
+		"json path":      {SampleRate: 1, Path: "/tmp/access.log"},
+	} {
+		_, err := NewAccessLogger(cfg)
+		assert.Error(t, err, name)
+	}
+}
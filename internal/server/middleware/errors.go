@@ -7,6 +7,7 @@ import (
 	"runtime/debug"
 
 	"github.com/fulmenhq/forge-workhorse-groningen/internal/metrics"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
 	"github.com/fulmenhq/gofulmen/errors"
 )
 
@@ -55,11 +56,12 @@ type ErrorDetail struct {
 
 // writeErrorResponse writes error response directly (avoid circular import)
 func writeErrorResponse(w http.ResponseWriter, envelope *errors.ErrorEnvelope, statusCode int) {
+	redactor := observability.ResponseRedactor()
 	response := ErrorResponse{
 		Error: ErrorDetail{
 			Code:      envelope.Code,
-			Message:   envelope.Message,
-			Details:   envelope.Context,
+			Message:   redactor.String(envelope.Message),
+			Details:   redactor.Map(envelope.Context),
 			RequestID: envelope.CorrelationID,
 		},
 	}
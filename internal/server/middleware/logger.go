@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+// routeField resolves the chi route pattern when an entry is written: the
+// logger is bound before chi matches a route, but handlers log after. The
+// pattern is frozen when the request ends, because chi recycles its routing
+// context and goroutines may still log with the request logger.
+type routeField struct {
+	mu     sync.Mutex
+	r      *http.Request
+	frozen string
+}
+
+func (f *routeField) String() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.r == nil {
+		return f.frozen
+	}
+	return EndpointPattern(f.r)
+}
+
+func (f *routeField) freeze() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.frozen = EndpointPattern(f.r)
+	f.r = nil
+}
+
+// LoggerComponent names the request loggers, so their level can be changed
+// on its own at /admin/loglevel
+const LoggerComponent = "http"
+
+// RequestLogger installs a request-scoped logger in the context, bound with
+// request_id, method, route, client_ip and, when the caller sent a traceparent,
+// trace_id. Handlers get it from observability.FromContext(r.Context()).
+// Mount it right after RequestID.
+func RequestLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if observability.ServerLogger == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		route := &routeField{r: r}
+		defer route.freeze()
+
+		fields := []zap.Field{
+			zap.String("request_id", GetRequestID(r.Context())),
+			zap.String("method", r.Method),
+			zap.Stringer("route", route),
+			zap.String("client_ip", clientIP(r)),
+		}
+		if traceID := traceIDFromHeader(r.Header.Get(TraceParentHeader)); traceID != "" {
+			fields = append(fields, zap.String("trace_id", traceID))
+		}
+
+		logger := observability.ServerLogger.Named(LoggerComponent).With(fields...)
+		next.ServeHTTP(w, r.WithContext(observability.WithLogger(r.Context(), logger)))
+	})
+}
+
+// clientIP returns the caller's address without the port. chi's RealIP has
+// already replaced RemoteAddr with X-Real-IP / X-Forwarded-For when present.
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+// setupFileLogger points ServerLogger at a JSON file and returns a reader for
+// the decoded entries
+func setupFileLogger(t *testing.T) func() []map[string]any {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "server.log")
+	logger, err := observability.NewLogger("test", config.LoggingConfig{
+		Level: "info",
+		Sinks: []config.LogSinkConfig{{Type: "file", Path: path}},
+	})
+	require.NoError(t, err)
+
+	original := observability.ServerLogger
+	observability.ServerLogger = logger
+	t.Cleanup(func() {
+		observability.ServerLogger = original
+		_ = logger.ReopenFiles()
+	})
+
+	return func() []map[string]any {
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		defer func() { _ = f.Close() }()
+
+		var entries []map[string]any
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+}
+
+func TestRequestLogger_BindsRequestFields(t *testing.T) {
+	readEntries := setupFileLogger(t)
+
+	accessLog, err := NewAccessLogger(config.AccessLogConfig{Enabled: true, SampleRate: 1})
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Use(RequestID, RequestLogger, accessLog.Middleware, RequestMetrics)
+	r.Get("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		observability.FromContext(r.Context()).Info("loaded item")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/items/42", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	req.Header.Set(RequestIDHeader, "req-123")
+	req.Header.Set(TraceParentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := readEntries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "loaded item", entries[0]["message"])
+	assert.Equal(t, "HTTP request completed", entries[1]["message"])
+	for _, entry := range entries {
+		assert.Equal(t, "req-123", entry["request_id"])
+		assert.Equal(t, "GET", entry["method"])
+		assert.Equal(t, "/items/{id}", entry["route"])
+		assert.Equal(t, "203.0.113.7", entry["client_ip"])
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entry["trace_id"])
+	}
+	assert.EqualValues(t, http.StatusNoContent, entries[1]["status"])
+	assert.Equal(t, AccessLogComponent, entries[1]["logger"])
+}
+
+func TestRequestLogger_RouteSurvivesRequestEnd(t *testing.T) {
+	readEntries := setupFileLogger(t)
+
+	var logger *observability.Logger
+	r := chi.NewRouter()
+	r.Use(RequestID, RequestLogger)
+	r.Get("/jobs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		logger = observability.FromContext(r.Context())
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/jobs/1", nil))
+
+	// chi has recycled the routing context; the pattern must not change
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unrouted", nil))
+	logger.Info("background work finished")
+
+	entries := readEntries()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "/jobs/{id}", entries[0]["route"])
+	assert.NotContains(t, entries[0], "trace_id")
+}
+
+func TestFromContext_FallsBackToServerLogger(t *testing.T) {
+	original := observability.ServerLogger
+	observability.ServerLogger = nil
+	t.Cleanup(func() { observability.ServerLogger = original })
+
+	// No logger yet: a no-op logger, never nil
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	require.NotNil(t, observability.FromContext(req.Context()))
+	observability.FromContext(req.Context()).Info("dropped")
+
+	readEntries := setupFileLogger(t)
+	assert.Same(t, observability.ServerLogger, observability.FromContext(req.Context()))
+	assert.Empty(t, readEntries())
+}
@@ -1,13 +1,18 @@
 package middleware
 
 import (
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/metrics"
 	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/slo"
+	"github.com/fulmenhq/gofulmen/telemetry"
 	"github.com/go-chi/chi/v5"
-	"go.uber.org/zap"
 )
 
 // responseWriter wraps http.ResponseWriter to capture status code and response size
@@ -28,8 +33,21 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
-// getEndpointPattern extracts chi route pattern to avoid high-cardinality paths
-func getEndpointPattern(r *http.Request) string {
+// countingBody wraps a request body to count the bytes the handler reads
+type countingBody struct {
+	io.ReadCloser
+	bytesRead int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.bytesRead += int64(n)
+	return n, err
+}
+
+// EndpointPattern returns the chi route pattern for r, falling back to a
+// small fixed set of names so raw paths never become metric labels
+func EndpointPattern(r *http.Request) string {
 	// Try to get chi route pattern
 	routePattern := chi.RouteContext(r.Context()).RoutePattern()
 	if routePattern != "" {
@@ -53,103 +71,142 @@ func getEndpointPattern(r *http.Request) string {
 	}
 }
 
+// Request instruments. Duration and size buckets come from metrics.buckets.
+var (
+	requestsTotal   = metrics.NewCounterVec(metrics.HTTPRequestsTotal, "method", "endpoint", "status")
+	errorsTotal     = metrics.NewCounterVec(metrics.HTTPErrorsTotal, "method", "endpoint", "status", "error_type")
+	requestDuration = metrics.NewHistogramVec(metrics.HTTPRequestDuration, telemetry.DefaultHistogramBucketsMS, "method", "endpoint", "status")
+	requestSize     = metrics.NewHistogramVec(metrics.HTTPRequestSizeBytes, observability.DefaultSizeBucketsBytes, "method", "endpoint")
+	responseSize    = metrics.NewHistogramVec(metrics.HTTPResponseSizeBytes, observability.DefaultSizeBucketsBytes, "method", "endpoint")
+)
+
+// statusLabels caches status code label values so requests don't format them
+var statusLabels = func() [600]string {
+	var labels [600]string
+	for code := range labels {
+		labels[code] = strconv.Itoa(code)
+	}
+	return labels
+}()
+
+func statusLabel(code int) string {
+	if code >= 0 && code < len(statusLabels) {
+		return statusLabels[code]
+	}
+	return strconv.Itoa(code)
+}
+
+// methodLabel maps the request method to a fixed set of label values. The
+// method is client-controlled, so anything but the standard verbs is
+// reported as OTHER.
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	default:
+		return "OTHER"
+	}
+}
+
+// TraceParentHeader is the W3C Trace Context header
+const TraceParentHeader = "traceparent"
+
+// exemplarLabels links a latency observation to the request's logs and, when
+// the caller propagated W3C trace context, to its trace
+func exemplarLabels(r *http.Request, requestID string) map[string]string {
+	traceID := traceIDFromHeader(r.Header.Get(TraceParentHeader))
+	if requestID == "" && traceID == "" {
+		return nil
+	}
+
+	labels := make(map[string]string, 2)
+	if requestID != "" {
+		labels["request_id"] = requestID
+	}
+	if traceID != "" {
+		labels["trace_id"] = traceID
+	}
+	return labels
+}
+
+// traceIDFromHeader extracts the trace ID from a traceparent header
+// (version-traceid-parentid-flags), returning "" when it is malformed
+func traceIDFromHeader(traceparent string) string {
+	parts := strings.Split(traceparent, "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return ""
+	}
+	traceID := parts[1]
+	if traceID == strings.Repeat("0", 32) {
+		return ""
+	}
+	for _, c := range traceID {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return ""
+		}
+	}
+	return traceID
+}
+
+// inFlight counts requests currently inside RequestMetrics
+var inFlight atomic.Int64
+
+// InFlightRequests returns the number of HTTP requests currently being served
+func InFlightRequests() int64 {
+	return inFlight.Load()
+}
+
 // RequestMetrics middleware captures HTTP request metrics following Prometheus standards
 func RequestMetrics(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if observability.TelemetrySystem == nil {
-			next.ServeHTTP(w, r)
-			return
-		}
+		inFlight.Add(1)
+		defer inFlight.Add(-1)
 
 		start := time.Now()
 		wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 
-		// Get request size from Content-Length header
-		requestSize := int64(0)
-		if contentLength := r.Header.Get("Content-Length"); contentLength != "" {
-			if size, err := strconv.ParseInt(contentLength, 10, 64); err == nil {
-				requestSize = size
-			}
+		// Count body bytes as the handler reads them; Content-Length is absent
+		// for chunked uploads and cannot be trusted anyway
+		var body *countingBody
+		if r.Body != nil && r.Body != http.NoBody {
+			body = &countingBody{ReadCloser: r.Body}
+			r.Body = body
 		}
 
 		next.ServeHTTP(wrapped, r)
 
-		duration := time.Since(start)
-		endpoint := getEndpointPattern(r)
-
-		// Common labels for all metrics (avoid high cardinality)
-		commonLabels := map[string]string{
-			"method":   r.Method,
-			"endpoint": endpoint,
-			"status":   strconv.Itoa(wrapped.statusCode),
+		bodySize := int64(0)
+		if body != nil {
+			bodySize = body.bytesRead
 		}
 
-		// Emit request counter
-		_ = observability.TelemetrySystem.Counter(
-			"http_requests_total",
-			1,
-			commonLabels,
-		)
-
-		// Emit duration histogram in milliseconds (keep gofulmen standard)
-		_ = observability.TelemetrySystem.Histogram(
-			"http_request_duration_ms",
-			duration,
-			commonLabels,
-		)
-
-		// Emit request size as gauge (not histogram since it's a single value)
-		_ = observability.TelemetrySystem.Gauge(
-			"http_request_size_bytes",
-			float64(requestSize),
-			map[string]string{
-				"method":   r.Method,
-				"endpoint": endpoint,
-			},
-		)
-
-		// Emit response size as gauge (not histogram since it's a single value)
-		_ = observability.TelemetrySystem.Gauge(
-			"http_response_size_bytes",
-			float64(wrapped.bytesWritten),
-			map[string]string{
-				"method":   r.Method,
-				"endpoint": endpoint,
-			},
-		)
-
-		// Emit error counter for non-2xx responses
-		if wrapped.statusCode >= 400 {
-			errorType := "client_error" // 4xx
-			if wrapped.statusCode >= 500 {
-				errorType = "server_error" // 5xx
-			}
+		duration := time.Since(start)
+		endpoint := EndpointPattern(r)
+		requestID := GetRequestID(r.Context())
 
-			_ = observability.TelemetrySystem.Counter(
-				"http_errors_total",
-				1,
-				map[string]string{
-					"method":     r.Method,
-					"endpoint":   endpoint,
-					"status":     strconv.Itoa(wrapped.statusCode),
-					"error_type": errorType,
-				},
-			)
+		// Metric emission is skipped entirely when metrics are disabled
+		if observability.TelemetrySystem != nil {
+			// Instruments are bound per label set once and reused
+			method := methodLabel(r.Method)
+			status := statusLabel(wrapped.statusCode)
+			requestsTotal.With(method, endpoint, status).Inc()
+			requestDuration.With(method, endpoint, status).
+				ObserveWithExemplar(float64(duration.Nanoseconds())/1e6, exemplarLabels(r, requestID))
+			requestSize.With(method, endpoint).Observe(float64(bodySize))
+			responseSize.With(method, endpoint).Observe(float64(wrapped.bytesWritten))
+
+			// Emit error counter for non-2xx responses
+			if wrapped.statusCode >= 400 {
+				errorType := "client_error" // 4xx
+				if wrapped.statusCode >= 500 {
+					errorType = "server_error" // 5xx
+				}
+				errorsTotal.With(method, endpoint, status, errorType).Inc()
+			}
 		}
 
-		// Log request with request ID for tracing (request ID stays in logs, not metrics)
-		requestID := GetRequestID(r.Context())
-		if observability.ServerLogger != nil {
-			observability.ServerLogger.Info("HTTP request completed",
-				zap.String("method", r.Method),
-				zap.String("path", r.URL.Path),
-				zap.String("endpoint", endpoint),
-				zap.Int("status", wrapped.statusCode),
-				zap.Duration("duration", duration),
-				zap.Int64("request_size", requestSize),
-				zap.Int64("response_size", wrapped.bytesWritten),
-				zap.String("requestID", requestID),
-			)
-		}
+		// SLOs are evaluated in-process and work with metrics disabled
+		slo.Record(endpoint, wrapped.statusCode, duration)
 	})
 }
@@ -1,11 +1,14 @@
 package middleware
 
 import (
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/metrics"
 	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
 	"github.com/fulmenhq/gofulmen/telemetry"
 	telemetrytesting "github.com/fulmenhq/gofulmen/telemetry/testing"
@@ -139,7 +142,7 @@ func TestRequestMetrics_WithResponseSize(t *testing.T) {
 		"expected http_response_size_bytes metric to be emitted")
 }
 
-func TestGetEndpointPattern_StandardPaths(t *testing.T) {
+func TestEndpointPattern_StandardPaths(t *testing.T) {
 	tests := []struct {
 		path     string
 		expected string
@@ -157,7 +160,7 @@ func TestGetEndpointPattern_StandardPaths(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.path, func(t *testing.T) {
 			req := httptest.NewRequest("GET", tt.path, nil)
-			pattern := getEndpointPattern(req)
+			pattern := EndpointPattern(req)
 			assert.Equal(t, tt.expected, pattern, "Path %s should map to pattern %s", tt.path, tt.expected)
 		})
 	}
@@ -203,3 +206,100 @@ func TestRequestMetrics_DurationMeasurement(t *testing.T) {
 	assert.Greater(t, collector.CountMetricsByName("http_request_duration_ms"), 0,
 		"expected http_request_duration_ms metric to be emitted")
 }
+
+func TestRequestMetrics_TracksInFlightRequests(t *testing.T) {
+	var during int64
+	handler := RequestMetrics(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		during = InFlightRequests()
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	before := InFlightRequests()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+
+	assert.Equal(t, before+1, during, "request should be counted while being served")
+	assert.Equal(t, before, InFlightRequests(), "request should be released when done")
+}
+
+func TestRequestMetrics_CountsBodyBytesRead(t *testing.T) {
+	collector := setupTelemetry(t)
+
+	handler := RequestMetrics(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// Chunked upload: no Content-Length header
+	req := httptest.NewRequest("POST", "/upload", strings.NewReader(strings.Repeat("x", 2048)))
+	req.ContentLength = -1
+	req.Header.Del("Content-Length")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	events := collector.GetMetricsByName("http_request_size_bytes")
+	require.Len(t, events, 1)
+	summary, ok := events[0].Value.(telemetry.HistogramSummary)
+	require.True(t, ok, "request size should be a histogram")
+	assert.Equal(t, int64(1), summary.Count)
+	assert.Equal(t, 2048.0, summary.Sum)
+}
+
+func TestExemplarLabels(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	assert.Nil(t, exemplarLabels(req, ""))
+	assert.Equal(t, map[string]string{"request_id": "req-1"}, exemplarLabels(req, "req-1"))
+
+	req.Header.Set(TraceParentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.Equal(t, map[string]string{
+		"request_id": "req-1",
+		"trace_id":   "4bf92f3577b34da6a3ce929d0e0e4736",
+	}, exemplarLabels(req, "req-1"))
+
+	for _, malformed := range []string{
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f35-00f067aa0ba902b7-01",
+		"garbage",
+	} {
+		assert.Empty(t, traceIDFromHeader(malformed), malformed)
+	}
+}
+
+func TestRequestMetrics_MatchCatalog(t *testing.T) {
+	collector := setupTelemetry(t)
+
+	handler := RequestMetrics(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("boom"))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/test", strings.NewReader("body")))
+
+	require.NotEmpty(t, collector.GetMetrics())
+	for _, m := range collector.GetMetrics() {
+		def, ok := metrics.Lookup(m.Name)
+		if !assert.True(t, ok, "%s is emitted but not in the metrics catalog", m.Name) {
+			continue
+		}
+		assert.Equal(t, string(def.Type), string(m.Type), m.Name)
+		assert.Len(t, m.Tags, len(def.Labels), m.Name)
+		for _, label := range def.Labels {
+			assert.Contains(t, m.Tags, label, m.Name)
+		}
+	}
+}
+
+func TestRequestMetrics_UnknownMethodsShareOneLabel(t *testing.T) {
+	collector := setupTelemetry(t)
+
+	handler := RequestMetrics(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	for _, method := range []string{"GET", "PROPFIND", "X-RANDOM-1", "X-RANDOM-2"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/test", nil))
+	}
+
+	methods := make(map[string]bool)
+	for _, event := range collector.GetMetricsByName(metrics.HTTPRequestsTotal) {
+		methods[event.Tags["method"]] = true
+	}
+	assert.Equal(t, map[string]bool{"GET": true, "OTHER": true}, methods)
+}
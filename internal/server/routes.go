@@ -2,6 +2,8 @@ package server
 
 import (
 	"context"
+	"crypto/subtle"
+	"net/http"
 	"os"
 
 	"github.com/fulmenhq/gofulmen/signals"
@@ -9,6 +11,8 @@ import (
 	"github.com/fulmenhq/forge-workhorse-groningen/internal/appid"
 	"go.uber.org/zap"
 
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+	apperrors "github.com/fulmenhq/forge-workhorse-groningen/internal/errors"
 	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
 	"github.com/fulmenhq/forge-workhorse-groningen/internal/server/handlers"
 )
@@ -16,10 +20,7 @@ import (
 // registerRoutes registers all HTTP routes
 func (s *Server) registerRoutes() {
 	// Standard health endpoints per Workhorse §9
-	s.router.Get("/health", handlers.HealthHandler)
-	s.router.Get("/health/live", handlers.LivenessHandler)
-	s.router.Get("/health/ready", handlers.ReadinessHandler)
-	s.router.Get("/health/startup", handlers.StartupHandler)
+	s.registerHealthEndpoints()
 
 	// Version endpoint
 	s.router.Get("/version", handlers.VersionHandler)
@@ -27,11 +28,45 @@ func (s *Server) registerRoutes() {
 	// Metrics endpoint (in server package to access HandleError)
 	s.router.Get("/metrics", MetricsHandler)
 
-	// Admin signal endpoint (optional, requires GRONINGEN_ADMIN_TOKEN)
+	// Admin surface: signal endpoint, health history, SLO report and log levels (optional, requires GRONINGEN_ADMIN_TOKEN)
 	s.registerAdminEndpoint()
 }
 
-// registerAdminEndpoint optionally registers the admin signal endpoint
+// registerHealthEndpoints mounts the health probes on their configured paths and aliases
+func (s *Server) registerHealthEndpoints() {
+	logger := observability.ServerLogger
+
+	if !s.health.Enabled {
+		if logger != nil {
+			logger.Info("Health endpoints disabled (health.enabled=false)")
+		}
+		return
+	}
+
+	probes := []struct {
+		cfg         config.HealthProbeConfig
+		defaultPath string
+		handler     http.HandlerFunc
+	}{
+		{s.health.Probes.Aggregate, "/health", handlers.HealthHandler},
+		{s.health.Probes.Live, "/health/live", handlers.LivenessHandler},
+		{s.health.Probes.Ready, "/health/ready", handlers.ReadinessHandler},
+		{s.health.Probes.Startup, "/health/startup", handlers.StartupHandler},
+	}
+
+	registered := make(map[string]bool)
+	for _, probe := range probes {
+		for _, p := range probe.cfg.Routes(probe.defaultPath) {
+			if p == "" || registered[p] {
+				continue
+			}
+			registered[p] = true
+			s.router.Get(p, probe.handler)
+		}
+	}
+}
+
+// registerAdminEndpoint optionally registers the admin surface (signal endpoint, health history, SLO report)
 func (s *Server) registerAdminEndpoint() {
 	// Get admin token from environment (identity-aware)
 	ctx := context.Background()
@@ -46,7 +81,7 @@ func (s *Server) registerAdminEndpoint() {
 
 	if adminToken == "" {
 		if logger != nil {
-			logger.Debug("Admin signal endpoint disabled (no " + envPrefix + "ADMIN_TOKEN set)")
+			logger.Debug("Admin endpoints disabled (no " + envPrefix + "ADMIN_TOKEN set)")
 		}
 		return
 	}
@@ -62,11 +97,49 @@ func (s *Server) registerAdminEndpoint() {
 	// Register admin endpoint
 	s.router.Post("/admin/signal", handler.ServeHTTP)
 
+	// Health history is diagnostic detail, so it lives behind the admin token
+	if s.health.Enabled {
+		s.router.With(requireAdminToken(adminToken)).Get("/health/history", handlers.HealthHistoryHandler)
+	}
+
+	// SLO report (error budgets, burn rates) for the configured objectives
+	s.router.With(requireAdminToken(adminToken)).Get("/admin/slo", handlers.SLOHandler)
+
+	// Runtime log levels, global or per component, optionally with a TTL
+	s.router.With(requireAdminToken(adminToken)).Get("/admin/loglevel", handlers.LogLevelHandler)
+	s.router.With(requireAdminToken(adminToken)).Put("/admin/loglevel", handlers.SetLogLevelHandler)
+
 	if logger != nil {
 		logger.Info("Admin signal endpoint enabled",
 			zap.String("path", "/admin/signal"),
 			zap.String("auth", "bearer token"),
 			zap.String("rate_limit", "10/min, burst 5"))
+		if s.health.Enabled {
+			logger.Info("Admin health history endpoint enabled",
+				zap.String("path", "/health/history"),
+				zap.String("auth", "bearer token"))
+		}
+		logger.Info("Admin SLO report endpoint enabled",
+			zap.String("path", "/admin/slo"),
+			zap.String("auth", "bearer token"))
+		logger.Info("Admin log level endpoint enabled",
+			zap.String("path", "/admin/loglevel"),
+			zap.String("auth", "bearer token"))
 		logger.Warn("Admin endpoint enabled - ensure this server is not exposed to public internet")
 	}
 }
+
+// requireAdminToken rejects requests that do not carry the admin bearer token
+func requireAdminToken(token string) func(http.Handler) http.Handler {
+	expected := []byte("Bearer " + token)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			provided := []byte(r.Header.Get("Authorization"))
+			if subtle.ConstantTimeCompare(provided, expected) != 1 {
+				HandleError(w, r, apperrors.NewUnauthorizedError("Valid admin bearer token required"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
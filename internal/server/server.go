@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
@@ -10,7 +11,9 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"go.uber.org/zap"
 
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
 	apperrors "github.com/fulmenhq/forge-workhorse-groningen/internal/errors"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/metrics"
 	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
 	"github.com/fulmenhq/forge-workhorse-groningen/internal/server/handlers"
 	servermw "github.com/fulmenhq/forge-workhorse-groningen/internal/server/middleware"
@@ -18,24 +21,63 @@ import (
 
 // Server represents the HTTP server
 type Server struct {
-	router *chi.Mux
-	server *http.Server
-	host   string
-	port   int
+	router    *chi.Mux
+	server    *http.Server
+	host      string
+	port      int
+	health    config.HealthConfig
+	accessLog *servermw.AccessLogger
+	conns     *connTracker
+	startTime time.Time
+}
+
+// Option customizes a Server before routes are registered
+type Option func(*Server)
+
+// WithHealthConfig controls whether health endpoints are mounted and on which paths
+func WithHealthConfig(cfg config.HealthConfig) Option {
+	return func(s *Server) {
+		s.health = cfg
+	}
+}
+
+// WithAccessLogger replaces the default access log, which logs every request
+// as JSON
+func WithAccessLogger(accessLog *servermw.AccessLogger) Option {
+	return func(s *Server) {
+		s.accessLog = accessLog
+	}
 }
 
 // New creates a new HTTP server instance
-func New(host string, port int) *Server {
+func New(host string, port int, opts ...Option) *Server {
 	r := chi.NewRouter()
 
+	s := &Server{
+		router: r,
+		host:   host,
+		port:   port,
+		health: config.HealthConfig{Enabled: true},
+		conns:  newConnTracker(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.accessLog == nil {
+		// Log every request as JSON, as before logging.access existed
+		s.accessLog, _ = servermw.NewAccessLogger(config.AccessLogConfig{Enabled: true, SampleRate: 1})
+	}
+
 	// Standard chi middleware
 	r.Use(middleware.RealIP)
 
-	// Our custom middleware in correct order (RequestID → Metrics → Logging → Recovery)
+	// Our custom middleware in correct order (RequestID → Logger → Access log → Metrics → Errors → Recovery)
 	r.Use(servermw.RequestID)      // 1. Request ID (early for correlation)
-	r.Use(servermw.RequestMetrics) // 2. Metrics (measure everything)
-	r.Use(servermw.ErrorHandler)   // 3. Error handling (after metrics)
-	r.Use(servermw.Recovery)       // 4. Panic recovery (outermost)
+	r.Use(servermw.RequestLogger)  // 2. Request-scoped logger (observability.FromContext)
+	r.Use(s.accessLog.Middleware)  // 3. Access log (after the logger binds request fields)
+	r.Use(servermw.RequestMetrics) // 4. Metrics (measure everything)
+	r.Use(servermw.ErrorHandler)   // 5. Error handling (after metrics)
+	r.Use(servermw.Recovery)       // 6. Panic recovery (outermost)
 
 	// Chi's Recoverer is redundant since we have our own Recovery middleware
 	// r.Use(middleware.Recoverer)
@@ -52,12 +94,6 @@ func New(host string, port int) *Server {
 		HandleError(w, req, err)
 	})
 
-	s := &Server{
-		router: r,
-		host:   host,
-		port:   port,
-	}
-
 	// Ensure handlers use the centralized error responder
 	handlers.SetHTTPErrorResponder(HandleError)
 
@@ -77,14 +113,36 @@ func (s *Server) Start() error {
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 30 * time.Second,
 		IdleTimeout:  120 * time.Second,
+		ConnState:    s.conns.track,
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
 	}
 
+	// Start time is recorded once the listener is bound; uptime, connection
+	// and in-flight gauges are refreshed on every scrape
+	s.startTime = time.Now()
+	metrics.SetServerStartTime(s.startTime.Unix())
+	observability.RegisterCollector(s.collectMetrics)
+
 	observability.ServerLogger.Info("Starting HTTP server",
 		zap.String("host", s.host),
 		zap.Int("port", s.port),
 		zap.String("addr", addr))
 
-	return s.server.ListenAndServe()
+	return s.server.Serve(listener)
+}
+
+// collectMetrics emits point-in-time server gauges
+func (s *Server) collectMetrics() {
+	open, active, idle := s.conns.counts()
+	metrics.SetOpenConnections(open)
+	metrics.SetActiveConnections(active)
+	metrics.SetIdleConnections(idle)
+	metrics.SetRequestsInFlight(servermw.InFlightRequests())
+	metrics.SetServerUptime(int64(time.Since(s.startTime).Seconds()))
 }
 
 // Shutdown gracefully shuts down the HTTP server
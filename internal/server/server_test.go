@@ -6,7 +6,9 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
 	apperrors "github.com/fulmenhq/forge-workhorse-groningen/internal/errors"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/server/handlers"
 )
 
 func TestServerUsesStandardErrorHandlers(t *testing.T) {
@@ -30,3 +32,80 @@ func TestServerUsesStandardErrorHandlers(t *testing.T) {
 		t.Fatalf("expected error code NOT_FOUND, got %s", body.Error.Code)
 	}
 }
+
+func TestServerHealthEndpointsRespectConfig(t *testing.T) {
+	handlers.InitHealthManager("test")
+
+	t.Run("Disabled", func(t *testing.T) {
+		srv := New("127.0.0.1", 0, WithHealthConfig(config.HealthConfig{Enabled: false}))
+
+		for _, path := range []string{"/health", "/health/live", "/health/ready", "/health/startup"} {
+			rec := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			if rec.Code != http.StatusNotFound {
+				t.Fatalf("expected %s to be unmounted, got status %d", path, rec.Code)
+			}
+		}
+	})
+
+	t.Run("CustomPathsAndAliases", func(t *testing.T) {
+		srv := New("127.0.0.1", 0, WithHealthConfig(config.HealthConfig{
+			Enabled: true,
+			Probes: config.HealthProbesConfig{
+				Aggregate: config.HealthProbeConfig{Aliases: []string{"/healthz"}},
+				Live:      config.HealthProbeConfig{Path: "/livez"},
+				Ready:     config.HealthProbeConfig{Aliases: []string{"/readyz"}},
+			},
+		}))
+
+		for path, want := range map[string]int{
+			"/health":         http.StatusOK,
+			"/healthz":        http.StatusOK,
+			"/livez":          http.StatusOK,
+			"/health/live":    http.StatusNotFound,
+			"/health/ready":   http.StatusOK,
+			"/readyz":         http.StatusOK,
+			"/health/startup": http.StatusOK,
+		} {
+			rec := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			if rec.Code != want {
+				t.Fatalf("expected %s to return %d, got %d", path, want, rec.Code)
+			}
+		}
+	})
+}
+
+func TestHealthHistoryRequiresAdminToken(t *testing.T) {
+	handlers.InitHealthManager("test")
+
+	t.Run("UnmountedWithoutToken", func(t *testing.T) {
+		t.Setenv("GRONINGEN_ADMIN_TOKEN", "")
+		srv := New("127.0.0.1", 0)
+
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/history", nil))
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("expected 404 without admin token, got %d", rec.Code)
+		}
+	})
+
+	t.Run("ProtectedWithToken", func(t *testing.T) {
+		t.Setenv("GRONINGEN_ADMIN_TOKEN", "s3cret")
+		srv := New("127.0.0.1", 0)
+
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/history", nil))
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401 without bearer token, got %d", rec.Code)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/health/history", nil)
+		req.Header.Set("Authorization", "Bearer s3cret")
+		rec = httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200 with bearer token, got %d", rec.Code)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package slo
+
+import "time"
+
+// slot counts the requests of one time slice
+type slot struct {
+	epoch int64
+	good  int64
+	total int64
+}
+
+// ring keeps request counts in fixed-width time slices covering a bounded span
+type ring struct {
+	width time.Duration
+	slots []slot
+}
+
+func newRing(width, span time.Duration) *ring {
+	n := int((span + width - 1) / width)
+	return &ring{width: width, slots: make([]slot, n+1)}
+}
+
+func (r *ring) epoch(t time.Time) int64 {
+	return t.UnixNano() / int64(r.width)
+}
+
+func (r *ring) add(t time.Time, good bool) {
+	epoch := r.epoch(t)
+	s := &r.slots[epoch%int64(len(r.slots))]
+	if s.epoch != epoch {
+		*s = slot{epoch: epoch}
+	}
+	s.total++
+	if good {
+		s.good++
+	}
+}
+
+// sum totals the slices overlapping the span ending at now, including the
+// current partial slice
+func (r *ring) sum(now time.Time, span time.Duration) (good, total int64) {
+	n := int64((span + r.width - 1) / r.width)
+	n = min(n, int64(len(r.slots)))
+	current := r.epoch(now)
+	for epoch := current - n + 1; epoch <= current; epoch++ {
+		s := r.slots[epoch%int64(len(r.slots))]
+		if s.epoch == epoch {
+			good += s.good
+			total += s.total
+		}
+	}
+	return good, total
+}
@@ -0,0 +1,392 @@
+// Package slo evaluates service level objectives in-process. Requests seen by
+// the HTTP metrics middleware are counted per objective in time slices, from
+// which the SLI, error budget and multi-window burn rates are derived without
+// Prometheus recording rules.
+package slo
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/metrics"
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
+)
+
+// Objective types
+const (
+	TypeAvailability = "availability"
+	TypeLatency      = "latency"
+)
+
+// DefaultWindow is the compliance period used when an objective sets none
+const DefaultWindow = 30 * 24 * time.Hour
+
+// Slices older than fineSpan are only kept at hourly resolution
+const (
+	fineWidth   = time.Minute
+	fineSpan    = 6 * time.Hour
+	coarseWidth = time.Hour
+)
+
+// burnWindow is a lookback window reported as a burn rate
+type burnWindow struct {
+	label string
+	span  time.Duration
+}
+
+// burnWindows pair long and short windows for the multi-window alerts below
+var burnWindows = []burnWindow{
+	{"5m", 5 * time.Minute},
+	{"30m", 30 * time.Minute},
+	{"1h", time.Hour},
+	{"2h", 2 * time.Hour},
+	{"6h", 6 * time.Hour},
+	{"1d", 24 * time.Hour},
+	{"3d", 72 * time.Hour},
+}
+
+// alertRule fires when both windows burn faster than threshold
+// (Google SRE workbook thresholds for a 30 day objective)
+type alertRule struct {
+	long, short string
+	threshold   float64
+}
+
+var (
+	pageRules   = []alertRule{{"1h", "5m", 14.4}, {"6h", "30m", 6}}
+	ticketRules = []alertRule{{"1d", "2h", 3}, {"3d", "6h", 1}}
+)
+
+// SLO gauges, refreshed on every scrape
+var (
+	targetGauge   = metrics.NewGaugeVec(metrics.SLOTargetRatio, "slo")
+	sliGauge      = metrics.NewGaugeVec(metrics.SLOSLIRatio, "slo")
+	budgetGauge   = metrics.NewGaugeVec(metrics.SLOErrorBudgetRemainingRatio, "slo")
+	burnRateGauge = metrics.NewGaugeVec(metrics.SLOBurnRate, "slo", "window")
+	alertGauge    = metrics.NewGaugeVec(metrics.SLOAlertFiring, "slo", "severity")
+	requestsGauge = metrics.NewGaugeVec(metrics.SLOWindowRequests, "slo", "result")
+)
+
+// Tracker counts requests against the configured objectives
+type Tracker struct {
+	objectives []*objective
+	now        func() time.Time
+}
+
+// objective is the runtime state of one configured objective
+type objective struct {
+	cfg       config.SLOObjectiveConfig
+	routes    map[string]bool
+	target    float64
+	window    time.Duration
+	windowTag string
+
+	mu     sync.Mutex
+	fine   *ring
+	coarse *ring
+}
+
+var global atomic.Pointer[Tracker]
+
+// New builds a tracker from cfg, validating every objective
+func New(cfg config.SLOConfig) (*Tracker, error) {
+	t := &Tracker{now: time.Now}
+	seen := make(map[string]bool)
+	for _, oc := range cfg.Objectives {
+		if oc.Name == "" {
+			return nil, fmt.Errorf("slo objective of type %q is missing a name", oc.Type)
+		}
+		if seen[oc.Name] {
+			return nil, fmt.Errorf("slo objective %q is declared more than once", oc.Name)
+		}
+		seen[oc.Name] = true
+
+		o, err := newObjective(oc)
+		if err != nil {
+			return nil, err
+		}
+		t.objectives = append(t.objectives, o)
+	}
+	return t, nil
+}
+
+func newObjective(cfg config.SLOObjectiveConfig) (*objective, error) {
+	cfg.Type = strings.ToLower(cfg.Type)
+	switch cfg.Type {
+	case TypeAvailability:
+	case TypeLatency:
+		if cfg.Threshold <= 0 {
+			return nil, fmt.Errorf("slo objective %q: latency requires threshold", cfg.Name)
+		}
+	default:
+		return nil, fmt.Errorf("slo objective %q: unknown type %q", cfg.Name, cfg.Type)
+	}
+	if cfg.Target <= 0 || cfg.Target >= 100 {
+		return nil, fmt.Errorf("slo objective %q: target must be a percentage in (0, 100)", cfg.Name)
+	}
+
+	window := DefaultWindow
+	if cfg.Window != "" {
+		parsed, err := ParseWindow(cfg.Window)
+		if err != nil {
+			return nil, fmt.Errorf("slo objective %q: %w", cfg.Name, err)
+		}
+		window = parsed
+	}
+	if window < time.Hour {
+		return nil, fmt.Errorf("slo objective %q: window must be at least 1h", cfg.Name)
+	}
+
+	o := &objective{
+		cfg:       cfg,
+		target:    cfg.Target / 100,
+		window:    window,
+		windowTag: FormatWindow(window),
+		fine:      newRing(fineWidth, fineSpan),
+		coarse:    newRing(coarseWidth, window),
+	}
+	if len(cfg.Routes) > 0 {
+		o.routes = make(map[string]bool, len(cfg.Routes))
+		for _, route := range cfg.Routes {
+			o.routes[route] = true
+		}
+	}
+	return o, nil
+}
+
+// Init installs the process-wide tracker used by Record. With no objectives
+// the tracker is cleared and Record becomes a no-op. When metrics are enabled
+// the SLO gauges are refreshed on every scrape.
+func Init(cfg config.SLOConfig) (*Tracker, error) {
+	if len(cfg.Objectives) == 0 {
+		global.Store(nil)
+		return nil, nil
+	}
+
+	t, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	global.Store(t)
+	if observability.TelemetrySystem != nil {
+		observability.RegisterCollector(t.Collect)
+	}
+	return t, nil
+}
+
+// Get returns the process-wide tracker, or nil when no objectives are configured
+func Get() *Tracker {
+	return global.Load()
+}
+
+// Record counts a completed request against the process-wide tracker
+func Record(endpoint string, status int, duration time.Duration) {
+	if t := global.Load(); t != nil {
+		t.Record(endpoint, status, duration)
+	}
+}
+
+// Record counts a completed request against every objective covering endpoint
+func (t *Tracker) Record(endpoint string, status int, duration time.Duration) {
+	now := t.now()
+	for _, o := range t.objectives {
+		if o.routes != nil && !o.routes[endpoint] {
+			continue
+		}
+
+		good := status < http.StatusInternalServerError
+		if o.cfg.Type == TypeLatency {
+			good = duration <= o.cfg.Threshold
+		}
+
+		o.mu.Lock()
+		o.fine.add(now, good)
+		o.coarse.add(now, good)
+		o.mu.Unlock()
+	}
+}
+
+// counts returns good and total requests over span
+func (o *objective) counts(now time.Time, span time.Duration) (good, total int64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if span <= fineSpan {
+		return o.fine.sum(now, span)
+	}
+	return o.coarse.sum(now, span)
+}
+
+// burnRate is how fast the error budget is spent over span: 1 means the
+// budget runs out exactly at the end of the window
+func (o *objective) burnRate(now time.Time, span time.Duration) float64 {
+	good, total := o.counts(now, span)
+	if total == 0 {
+		return 0
+	}
+	errorRatio := float64(total-good) / float64(total)
+	return errorRatio / (1 - o.target)
+}
+
+// Report is the JSON document served at /admin/slo
+type Report struct {
+	GeneratedAt time.Time         `json:"generated_at"`
+	Objectives  []ObjectiveReport `json:"objectives"`
+}
+
+// ObjectiveReport is the evaluation of one objective
+type ObjectiveReport struct {
+	Name        string             `json:"name"`
+	Type        string             `json:"type"`
+	Routes      []string           `json:"routes,omitempty"`
+	Target      float64            `json:"target"`
+	ThresholdMS float64            `json:"threshold_ms,omitempty"`
+	Window      string             `json:"window"`
+	Total       int64              `json:"total"`
+	Good        int64              `json:"good"`
+	SLI         float64            `json:"sli"`
+	ErrorBudget ErrorBudget        `json:"error_budget"`
+	BurnRates   map[string]float64 `json:"burn_rates"`
+	Alerts      Alerts             `json:"alerts"`
+}
+
+// ErrorBudget describes how many bad requests the objective tolerates over
+// its window and how much of that allowance is used
+type ErrorBudget struct {
+	Allowed        float64 `json:"allowed"`
+	Consumed       int64   `json:"consumed"`
+	RemainingRatio float64 `json:"remaining_ratio"`
+}
+
+// Alerts reports the multi-window burn-rate alerts: page for fast burns that
+// exhaust the budget within days, ticket for slow burns
+type Alerts struct {
+	Page   bool `json:"page"`
+	Ticket bool `json:"ticket"`
+}
+
+// Report evaluates every objective at the current time
+func (t *Tracker) Report() Report {
+	now := t.now()
+	report := Report{GeneratedAt: now.UTC(), Objectives: make([]ObjectiveReport, 0, len(t.objectives))}
+	for _, o := range t.objectives {
+		report.Objectives = append(report.Objectives, o.report(now))
+	}
+	return report
+}
+
+func (o *objective) report(now time.Time) ObjectiveReport {
+	good, total := o.counts(now, o.window)
+	r := ObjectiveReport{
+		Name:      o.cfg.Name,
+		Type:      o.cfg.Type,
+		Routes:    o.cfg.Routes,
+		Target:    o.cfg.Target,
+		Window:    o.windowTag,
+		Total:     total,
+		Good:      good,
+		SLI:       100,
+		BurnRates: make(map[string]float64, len(burnWindows)+1),
+	}
+	if o.cfg.Type == TypeLatency {
+		r.ThresholdMS = float64(o.cfg.Threshold.Nanoseconds()) / 1e6
+	}
+
+	r.ErrorBudget = ErrorBudget{
+		Allowed:        roundReport(float64(total) * (1 - o.target)),
+		Consumed:       total - good,
+		RemainingRatio: 1,
+	}
+	if total > 0 {
+		r.SLI = float64(good) / float64(total) * 100
+		// Remaining goes negative once the budget is overspent
+		r.ErrorBudget.RemainingRatio = 1 - float64(total-good)/(float64(total)*(1-o.target))
+	}
+
+	for _, w := range burnWindows {
+		if w.span <= o.window {
+			r.BurnRates[w.label] = o.burnRate(now, w.span)
+		}
+	}
+	r.BurnRates[o.windowTag] = o.burnRate(now, o.window)
+
+	r.Alerts = Alerts{
+		Page:   firing(pageRules, r.BurnRates),
+		Ticket: firing(ticketRules, r.BurnRates),
+	}
+	return r
+}
+
+func firing(rules []alertRule, rates map[string]float64) bool {
+	for _, rule := range rules {
+		long, okLong := rates[rule.long]
+		short, okShort := rates[rule.short]
+		if okLong && okShort && long > rule.threshold && short > rule.threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// Collect publishes the current evaluation as gauges
+func (t *Tracker) Collect() {
+	for _, r := range t.Report().Objectives {
+		targetGauge.With(r.Name).Set(r.Target / 100)
+		sliGauge.With(r.Name).Set(r.SLI / 100)
+		budgetGauge.With(r.Name).Set(r.ErrorBudget.RemainingRatio)
+		requestsGauge.With(r.Name, "good").Set(float64(r.Good))
+		requestsGauge.With(r.Name, "bad").Set(float64(r.Total - r.Good))
+		for window, rate := range r.BurnRates {
+			burnRateGauge.With(r.Name, window).Set(rate)
+		}
+		alertGauge.With(r.Name, "page").Set(boolGauge(r.Alerts.Page))
+		alertGauge.With(r.Name, "ticket").Set(boolGauge(r.Alerts.Ticket))
+	}
+}
+
+// roundReport trims floating point noise (1 - 0.999 is not exactly 0.001)
+func roundReport(v float64) float64 {
+	return math.Round(v*1e9) / 1e9
+}
+
+func boolGauge(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// ParseWindow parses a compliance window: any Go duration, or a whole
+// number of days such as 30d
+func ParseWindow(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid window %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid window %q", s)
+	}
+	return d, nil
+}
+
+// FormatWindow renders whole days as Nd and anything else as a Go duration
+func FormatWindow(d time.Duration) string {
+	day := 24 * time.Hour
+	if d%day == 0 {
+		return strconv.FormatInt(int64(d/day), 10) + "d"
+	}
+	if d%time.Hour == 0 {
+		return strconv.FormatInt(int64(d/time.Hour), 10) + "h"
+	}
+	return d.String()
+}
@@ -0,0 +1,142 @@
+package slo
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
+)
+
+// fakeClock lets tests move time forward between requests
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func newTestTracker(t *testing.T, objectives ...config.SLOObjectiveConfig) (*Tracker, *fakeClock) {
+	t.Helper()
+	tracker, err := New(config.SLOConfig{Objectives: objectives})
+	require.NoError(t, err)
+	clock := &fakeClock{t: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	tracker.now = clock.now
+	return tracker, clock
+}
+
+func record(tracker *Tracker, endpoint string, status int, duration time.Duration, n int) {
+	for i := 0; i < n; i++ {
+		tracker.Record(endpoint, status, duration)
+	}
+}
+
+func TestAvailabilityErrorBudget(t *testing.T) {
+	tracker, _ := newTestTracker(t, config.SLOObjectiveConfig{
+		Name:   "api",
+		Type:   TypeAvailability,
+		Routes: []string{"/items"},
+		Target: 99,
+	})
+
+	record(tracker, "/items", http.StatusOK, time.Millisecond, 95)
+	record(tracker, "/items", http.StatusNotFound, time.Millisecond, 4)
+	record(tracker, "/items", http.StatusServiceUnavailable, time.Millisecond, 1)
+	record(tracker, "/other", http.StatusServiceUnavailable, time.Millisecond, 50)
+
+	report := tracker.Report().Objectives[0]
+	assert.Equal(t, int64(100), report.Total, "requests to other routes are not counted")
+	assert.Equal(t, int64(99), report.Good, "4xx responses count as available")
+	assert.InDelta(t, 99.0, report.SLI, 1e-9)
+	assert.InDelta(t, 1.0, report.ErrorBudget.Allowed, 1e-9)
+	assert.Equal(t, int64(1), report.ErrorBudget.Consumed)
+	assert.InDelta(t, 0.0, report.ErrorBudget.RemainingRatio, 1e-9)
+	assert.InDelta(t, 1.0, report.BurnRates["5m"], 1e-9)
+	assert.InDelta(t, 1.0, report.BurnRates["30d"], 1e-9)
+	assert.False(t, report.Alerts.Page)
+}
+
+func TestLatencyObjective(t *testing.T) {
+	tracker, _ := newTestTracker(t, config.SLOObjectiveConfig{
+		Name:      "latency",
+		Type:      TypeLatency,
+		Target:    95,
+		Threshold: 300 * time.Millisecond,
+	})
+
+	record(tracker, "/a", http.StatusOK, 100*time.Millisecond, 9)
+	record(tracker, "/b", http.StatusOK, time.Second, 1)
+
+	report := tracker.Report().Objectives[0]
+	assert.Equal(t, int64(10), report.Total, "no routes matches every route")
+	assert.Equal(t, int64(9), report.Good)
+	assert.InDelta(t, 300.0, report.ThresholdMS, 1e-9)
+	assert.InDelta(t, 2.0, report.BurnRates["1h"], 1e-9)
+}
+
+func TestBurnRateWindowsAndAlerts(t *testing.T) {
+	tracker, clock := newTestTracker(t, config.SLOObjectiveConfig{
+		Name:   "api",
+		Type:   TypeAvailability,
+		Target: 99.9,
+	})
+
+	// 5% errors burns a 99.9% budget 50x: both page and ticket alerts fire
+	record(tracker, "/", http.StatusOK, time.Millisecond, 95)
+	record(tracker, "/", http.StatusInternalServerError, time.Millisecond, 5)
+	report := tracker.Report().Objectives[0]
+	assert.InDelta(t, 50.0, report.BurnRates["5m"], 1e-6)
+	assert.True(t, report.Alerts.Page)
+	assert.True(t, report.Alerts.Ticket)
+
+	// Two hours of clean traffic later the short windows have recovered
+	clock.t = clock.t.Add(2 * time.Hour)
+	record(tracker, "/", http.StatusOK, time.Millisecond, 100)
+	report = tracker.Report().Objectives[0]
+	assert.Zero(t, report.BurnRates["5m"])
+	assert.Zero(t, report.BurnRates["1h"])
+	assert.InDelta(t, 25.0, report.BurnRates["6h"], 1e-6)
+	assert.False(t, report.Alerts.Page)
+	assert.Equal(t, int64(200), report.Total)
+
+	// Outside the compliance window nothing is left
+	clock.t = clock.t.Add(31 * 24 * time.Hour)
+	report = tracker.Report().Objectives[0]
+	assert.Zero(t, report.Total)
+	assert.InDelta(t, 100.0, report.SLI, 1e-9)
+	assert.InDelta(t, 1.0, report.ErrorBudget.RemainingRatio, 1e-9)
+}
+
+func TestNewValidatesObjectives(t *testing.T) {
+	for name, oc := range map[string]config.SLOObjectiveConfig{
+		"missing name":      {Type: TypeAvailability, Target: 99},
+		"unknown type":      {Name: "x", Type: "throughput", Target: 99},
+		"latency threshold": {Name: "x", Type: TypeLatency, Target: 99},
+		"target range":      {Name: "x", Type: TypeAvailability, Target: 100},
+		"bad window":        {Name: "x", Type: TypeAvailability, Target: 99, Window: "a month"},
+		"short window":      {Name: "x", Type: TypeAvailability, Target: 99, Window: "5m"},
+	} {
+		_, err := New(config.SLOConfig{Objectives: []config.SLOObjectiveConfig{oc}})
+		assert.Error(t, err, name)
+	}
+
+	dup := config.SLOObjectiveConfig{Name: "x", Type: TypeAvailability, Target: 99}
+	_, err := New(config.SLOConfig{Objectives: []config.SLOObjectiveConfig{dup, dup}})
+	assert.Error(t, err, "duplicate names")
+}
+
+func TestParseAndFormatWindow(t *testing.T) {
+	for in, want := range map[string]time.Duration{
+		"30d":   30 * 24 * time.Hour,
+		"7d":    7 * 24 * time.Hour,
+		"36h":   36 * time.Hour,
+		"90m0s": 90 * time.Minute,
+	} {
+		got, err := ParseWindow(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	assert.Equal(t, "30d", FormatWindow(30*24*time.Hour))
+	assert.Equal(t, "36h", FormatWindow(36*time.Hour))
+	assert.Equal(t, "1h30m0s", FormatWindow(90*time.Minute))
+}
@@ -0,0 +1,20 @@
+#!/usr/bin/env bash
+
+set -euo pipefail
+
+DST_DIR=${1:-internal/assets/config}
+SOURCES=(
+    schemas/groningen/v1.0.0/config.schema.json
+    config/groningen/v1.0.0/groningen-defaults.yaml
+)
+
+mkdir -p "${DST_DIR}"
+
+for SRC in "${SOURCES[@]}"; do
+    if [ ! -f "${SRC}" ]; then
+        echo "❌ Missing source config file: ${SRC}" >&2
+        exit 1
+    fi
+    cp "${SRC}" "${DST_DIR}/$(basename "${SRC}")"
+    echo "✅ Synced ${SRC} → ${DST_DIR}/$(basename "${SRC}")"
+done
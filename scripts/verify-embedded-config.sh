@@ -0,0 +1,30 @@
+#!/usr/bin/env bash
+
+set -euo pipefail
+
+DST_DIR=${1:-internal/assets/config}
+SOURCES=(
+    schemas/groningen/v1.0.0/config.schema.json
+    config/groningen/v1.0.0/groningen-defaults.yaml
+)
+
+for SRC in "${SOURCES[@]}"; do
+    DST="${DST_DIR}/$(basename "${SRC}")"
+    if [ ! -f "${SRC}" ]; then
+        echo "❌ Missing source config file: ${SRC}" >&2
+        exit 1
+    fi
+    if [ ! -f "${DST}" ]; then
+        echo "❌ Missing embedded config mirror: ${DST}" >&2
+        echo "Run: make sync-embedded-config" >&2
+        exit 1
+    fi
+    if ! cmp -s "${SRC}" "${DST}"; then
+        echo "❌ Embedded config mirror is out of sync" >&2
+        echo "  ${SRC} != ${DST}" >&2
+        echo "Run: make sync-embedded-config" >&2
+        exit 1
+    fi
+done
+
+echo "✅ Embedded config mirror is in sync"
@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/fulmenhq/forge-workhorse-groningen/internal/config"
 	"github.com/fulmenhq/forge-workhorse-groningen/internal/observability"
 	"github.com/fulmenhq/forge-workhorse-groningen/internal/server"
 	"github.com/fulmenhq/forge-workhorse-groningen/internal/server/handlers"
@@ -26,10 +27,7 @@ import (
 func cleanupMetrics(t *testing.T) {
 	t.Helper()
 	t.Cleanup(func() {
-		if observability.PrometheusExporter != nil {
-			_ = observability.PrometheusExporter.Stop()
-			observability.PrometheusExporter = nil
-		}
+		observability.MetricsRegistry = nil
 		observability.TelemetrySystem = nil
 	})
 }
@@ -100,7 +98,7 @@ func newTestServer(t *testing.T, setup func(*chi.Mux)) (*httptest.Server, *http.
 
 func TestMetricsEndpoint_Integration(t *testing.T) {
 	observability.InitCLILogger("test", false)
-	observability.InitServerLogger("test", "info")
+	require.NoError(t, observability.InitServerLogger("test", config.LoggingConfig{Level: "info"}))
 
 	initMetricsOrSkip(t)
 
@@ -180,7 +178,7 @@ func TestMetricsEndpoint_Integration(t *testing.T) {
 
 func TestMetricsEndpoint_PrometheusFormat(t *testing.T) {
 	observability.InitCLILogger("test", false)
-	observability.InitServerLogger("test", "info")
+	require.NoError(t, observability.InitServerLogger("test", config.LoggingConfig{Level: "info"}))
 
 	initMetricsOrSkip(t)
 
@@ -238,14 +236,14 @@ func TestMetricsEndpoint_PrometheusFormat(t *testing.T) {
 
 func TestMetricsEndpoint_WithTelemetryDisabled(t *testing.T) {
 	observability.InitCLILogger("test", false)
-	observability.InitServerLogger("test", "info")
+	require.NoError(t, observability.InitServerLogger("test", config.LoggingConfig{Level: "info"}))
 
-	originalExporter := observability.PrometheusExporter
+	originalExporter := observability.MetricsRegistry
 	originalTelemetry := observability.TelemetrySystem
-	observability.PrometheusExporter = nil
+	observability.MetricsRegistry = nil
 	observability.TelemetrySystem = nil
 	t.Cleanup(func() {
-		observability.PrometheusExporter = originalExporter
+		observability.MetricsRegistry = originalExporter
 		observability.TelemetrySystem = originalTelemetry
 	})
 